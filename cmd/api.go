@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+// apiResponseOutput represents the decoded body of a raw API response. As
+// the structure of the data returned depends on the API endpoint requested,
+// it cannot be reflected upon like other outputters: the "table" format
+// renders it as indented JSON, and the "text" format applies the user's
+// output template on the decoded data.
+type apiResponseOutput struct {
+	data interface{}
+}
+
+func (o *apiResponseOutput) toJSON() { outputJSON(o.data) }
+
+func (o *apiResponseOutput) toText() {
+	if gOutputTemplate == "" {
+		o.toTable()
+		return
+	}
+
+	t, err := template.New("out").Parse(gOutputTemplate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to encode output in plaintext using template: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := t.Execute(os.Stdout, o.data); err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Println()
+}
+
+func (o *apiResponseOutput) toTable() {
+	j, err := json.MarshalIndent(o.data, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to encode output to JSON: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(j))
+}
+
+type apiCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"api"`
+
+	Method string `cli-arg:"#" cli-usage:"METHOD"`
+	Path   string `cli-arg:"#" cli-usage:"PATH"`
+
+	Data        string   `cli-short:"d" cli-usage:"request body JSON document, or @FILE to read it from a file (@- to read from standard input)"`
+	Environment string   `cli-usage:"API environment (default: current account's environment)" cli-hidden:""`
+	Headers     []string `cli-flag:"header" cli-short:"H" cli-usage:"additional request header (format: KEY:VALUE, can be specified multiple times)"`
+	NoPaginate  bool     `cli-flag:"no-paginate" cli-usage:"don't follow paginated responses"`
+	NoWait      bool     `cli-flag:"no-wait" cli-usage:"don't wait for the completion of asynchronous operations"`
+	Zone        string   `cli-short:"z" cli-usage:"zone to send the request to"`
+}
+
+func (c *apiCmd) cmdAliases() []string { return nil }
+
+func (c *apiCmd) cmdShort() string { return "Perform a raw Exoscale API request" }
+
+func (c *apiCmd) cmdLong() string {
+	return `This command performs a signed request to the Exoscale API V2 and prints
+the response body. It can be used to reach API endpoints not yet covered by
+dedicated exo CLI commands.
+
+If the API returns an asynchronous operation, the command waits for it to
+complete and prints the resource it references (unless --no-wait is set).
+
+If the response of a GET request is paginated (i.e. it references a next
+page using a "Link" header), the following pages are requested as well and
+their items are merged into a single response (unless --no-paginate is set).
+
+Examples:
+
+    exo api GET /v2/instance -z ch-gva-2
+    exo api POST /v2/security-group --data '{"name": "web"}'
+    exo api PUT /v2/instance/<ID> --data @instance.json
+`
+}
+
+func (c *apiCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *apiCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	c.Method = strings.ToUpper(c.Method)
+
+	if c.Environment == "" {
+		c.Environment = gCurrentAccount.Environment
+	}
+
+	var body []byte
+	if c.Data != "" {
+		var err error
+		if body, err = readAPIRequestData(c.Data); err != nil {
+			return fmt.Errorf("unable to read request body: %w", err)
+		}
+
+		if !json.Valid(body) {
+			return fmt.Errorf("request body is not a valid JSON document")
+		}
+	}
+
+	res, next, err := c.do(c.Method, c.Path, body)
+	if err != nil {
+		return err
+	}
+
+	for !c.NoPaginate && c.Method == http.MethodGet && next != "" {
+		var page interface{}
+		if page, next, err = c.do(http.MethodGet, next, nil); err != nil {
+			return err
+		}
+		res = mergeAPIResponsePages(res, page)
+	}
+
+	if !c.NoWait && c.Method != http.MethodGet {
+		if res, err = c.followOperation(res); err != nil {
+			return err
+		}
+	}
+
+	return c.outputFunc(&apiResponseOutput{data: res}, nil)
+}
+
+// do performs a signed API request, and returns the decoded response body
+// as well as the URL of the next page of results if the response is
+// paginated. The path can also be an absolute URL (e.g. a next page link).
+func (c *apiCmd) do(method, path string, body []byte) (interface{}, string, error) {
+	security, err := exoapi.NewSecurityProvider(gCurrentAccount.Key, gCurrentAccount.APISecret())
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to initialize API security provider: %w", err)
+	}
+
+	reqURL := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		reqURL = buildServerURL(c.Zone, c.Environment) + "/" +
+			strings.TrimPrefix(strings.TrimPrefix(path, "/"), "v2/")
+	}
+
+	req, err := http.NewRequestWithContext(gContext, method, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, h := range c.Headers {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 {
+			return nil, "", fmt.Errorf("invalid header %q, expected format KEY:VALUE", h)
+		}
+		req.Header.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	if err := security.Intercept(gContext, req); err != nil {
+		return nil, "", fmt.Errorf("unable to sign API request: %w", err)
+	}
+
+	transport, err := newHTTPTransport(gCurrentAccount)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to initialize HTTP transport: %w", err)
+	}
+
+	client := &http.Client{
+		Transport: newCLIRoundTripper(transport, gCurrentAccount.CustomHeaders),
+		Timeout:   time.Minute * time.Duration(gCurrentAccount.ClientTimeout),
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read API response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, "", fmt.Errorf("API request error: unexpected status %s: %s",
+			resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var res interface{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &res); err != nil {
+			return nil, "", fmt.Errorf("unable to decode API response: %w", err)
+		}
+	}
+
+	return res, apiNextPageLink(req.URL, resp.Header), nil
+}
+
+// followOperation checks whether res is an API asynchronous operation, and
+// if so polls it until completion then returns the resource referenced by
+// the operation (if any). If res is not an operation, it is returned as-is.
+func (c *apiCmd) followOperation(res interface{}) (interface{}, error) {
+	op, ok := res.(map[string]interface{})
+	if !ok {
+		return res, nil
+	}
+
+	id, hasID := op["id"].(string)
+	_, hasState := op["state"].(string)
+	_, hasReference := op["reference"]
+	if !hasID || !hasState || !hasReference {
+		return res, nil
+	}
+
+	var err error
+	decorateAsyncOperation(fmt.Sprintf("Waiting for operation %s to complete...", id), func() {
+		_, err = oapi.NewPoller().
+			WithTimeout(time.Minute*time.Duration(gCurrentAccount.ClientTimeout)).
+			Poll(gContext, func(_ context.Context) (bool, interface{}, error) {
+				var pollErr error
+				if res, _, pollErr = c.do(http.MethodGet, "/operation/"+id, nil); pollErr != nil {
+					return true, nil, pollErr
+				}
+
+				op, _ := res.(map[string]interface{})
+				if state, _ := op["state"].(string); state == "pending" {
+					return false, nil, nil
+				}
+
+				return true, nil, nil
+			})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	op, _ = res.(map[string]interface{})
+	if state, _ := op["state"].(string); state != "success" {
+		return nil, fmt.Errorf("operation %s %s: %v", id, state, op["reason"])
+	}
+
+	if ref, ok := op["reference"].(map[string]interface{}); ok {
+		if link, ok := ref["link"].(string); ok && link != "" && c.Method != http.MethodDelete {
+			res, _, err := c.do(http.MethodGet, link, nil)
+			return res, err
+		}
+	}
+
+	return op, nil
+}
+
+// apiNextPageLink returns the URL of the next page of results referenced by
+// the "Link" response header (RFC 8288) resolved against the request URL, or
+// an empty string if there is none.
+func apiNextPageLink(reqURL *url.URL, h http.Header) string {
+	for _, v := range h.Values("Link") {
+		for _, link := range strings.Split(v, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(kv) != 2 || strings.ToLower(kv[0]) != "rel" {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(kv[1], `"`)) {
+					if rel != "next" {
+						continue
+					}
+					next, err := reqURL.Parse(strings.Trim(target, "<>"))
+					if err != nil {
+						return ""
+					}
+					return next.String()
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// mergeAPIResponsePages merges a page of results into the results
+// accumulated so far: lists are concatenated, either at the top level or
+// for each list-typed property of an object. Other properties are set to
+// the value of the latest page.
+func mergeAPIResponsePages(acc, page interface{}) interface{} {
+	switch p := page.(type) {
+	case []interface{}:
+		if a, ok := acc.([]interface{}); ok {
+			return append(a, p...)
+		}
+
+	case map[string]interface{}:
+		a, ok := acc.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		for k, v := range p {
+			items, isList := v.([]interface{})
+			prev, wasList := a[k].([]interface{})
+			if isList && wasList {
+				a[k] = append(prev, items...)
+				continue
+			}
+			a[k] = v
+		}
+		return a
+	}
+
+	return page
+}
+
+// readAPIRequestData returns the request body data specified via the
+// "--data" flag: either a literal value, or the content of the file
+// specified after the "@" character ("-" meaning standard input).
+func readAPIRequestData(v string) ([]byte, error) {
+	if !strings.HasPrefix(v, "@") {
+		return []byte(v), nil
+	}
+
+	if v == "@-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(strings.TrimPrefix(v, "@"))
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(RootCmd, &apiCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
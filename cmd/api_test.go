@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_apiNextPageLink(t *testing.T) {
+	reqURL, _ := url.Parse("https://api.example.net/v2/instance")
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "no header", header: "", want: ""},
+		{
+			name:   "absolute",
+			header: `<https://api.example.net/v2/instance?page=2>; rel="next"`,
+			want:   "https://api.example.net/v2/instance?page=2",
+		},
+		{
+			name:   "relative among other relations",
+			header: `</v2/instance?page=1>; rel="prev", </v2/instance?page=3>; rel="next"`,
+			want:   "https://api.example.net/v2/instance?page=3",
+		},
+		{name: "no next relation", header: `</v2/instance?page=1>; rel="prev"`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.header != "" {
+				h.Set("Link", tt.header)
+			}
+			if got := apiNextPageLink(reqURL, h); got != tt.want {
+				t.Errorf("apiNextPageLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mergeAPIResponsePages(t *testing.T) {
+	acc := map[string]interface{}{"instances": []interface{}{"a"}, "total": 2.0}
+	page := map[string]interface{}{"instances": []interface{}{"b"}, "total": 2.0}
+
+	want := map[string]interface{}{"instances": []interface{}{"a", "b"}, "total": 2.0}
+	if got := mergeAPIResponsePages(acc, page); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeAPIResponsePages() = %v, want %v", got, want)
+	}
+
+	if got := mergeAPIResponsePages([]interface{}{1.0}, []interface{}{2.0}); !reflect.DeepEqual(got, []interface{}{1.0, 2.0}) {
+		t.Errorf("mergeAPIResponsePages() = %v", got)
+	}
+}
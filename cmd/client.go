@@ -56,12 +56,40 @@ func buildClient() {
 		return
 	}
 
-	httpClient := &http.Client{Transport: newCLIRoundTripper(http.DefaultTransport, gCurrentAccount.CustomHeaders)}
+	var err error
+	if cs, err = newClient(gCurrentAccount); err != nil {
+		panic(err.Error())
+	}
+
+	transport, err := newHTTPTransport(gCurrentAccount)
+	if err != nil {
+		panic(err.Error())
+	}
 
-	cs = egoscale.NewClient(
-		gCurrentAccount.Endpoint,
+	csRunstatus = egoscale.NewClient(gCurrentAccount.RunstatusEndpoint,
 		gCurrentAccount.Key,
 		gCurrentAccount.APISecret(),
+		egoscale.WithHTTPClient(&http.Client{
+			Transport: newCLIRoundTripper(transport, gCurrentAccount.CustomHeaders),
+		}))
+}
+
+// newClient returns an Exoscale API client initialized from the specified
+// account configuration.
+func newClient(acc *account) (*egoscale.Client, error) {
+	apiSecret := acc.APISecret()
+
+	transport, err := newHTTPTransport(acc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize HTTP transport: %w", err)
+	}
+
+	httpClient := &http.Client{Transport: newCLIRoundTripper(transport, acc.CustomHeaders)}
+
+	client := egoscale.NewClient(
+		acc.Endpoint,
+		acc.Key,
+		apiSecret,
 		egoscale.WithHTTPClient(httpClient),
 		egoscale.WithoutV2Client())
 
@@ -70,13 +98,13 @@ func buildClient() {
 	// (http.Transport) clashes.
 	// This can be removed once the only API used is V2.
 	clientExoV2, err := exov2.NewClient(
-		gCurrentAccount.Key,
-		gCurrentAccount.APISecret(),
-		exov2.ClientOptWithAPIEndpoint(gCurrentAccount.Endpoint),
-		exov2.ClientOptWithTimeout(time.Minute*time.Duration(gCurrentAccount.ClientTimeout)),
+		acc.Key,
+		apiSecret,
+		exov2.ClientOptWithAPIEndpoint(acc.Endpoint),
+		exov2.ClientOptWithTimeout(time.Minute*time.Duration(acc.ClientTimeout)),
 		exov2.ClientOptWithHTTPClient(func() *http.Client {
 			return &http.Client{
-				Transport: newCLIRoundTripper(http.DefaultTransport, gCurrentAccount.CustomHeaders),
+				Transport: newCLIRoundTripper(transport.Clone(), acc.CustomHeaders),
 			}
 		}()),
 		exov2.ClientOptCond(func() bool {
@@ -87,11 +115,9 @@ func buildClient() {
 		}, exov2.ClientOptWithTrace()),
 	)
 	if err != nil {
-		panic(fmt.Sprintf("unable to initialize Exoscale API V2 client: %v", err))
+		return nil, fmt.Errorf("unable to initialize Exoscale API V2 client: %v", err)
 	}
-	cs.Client = clientExoV2
+	client.Client = clientExoV2
 
-	csRunstatus = egoscale.NewClient(gCurrentAccount.RunstatusEndpoint,
-		gCurrentAccount.Key,
-		gCurrentAccount.APISecret())
+	return client, nil
 }
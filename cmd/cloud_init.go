@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cloudInitTemplateExt is the file extension of cloud-init user data files to
+// be rendered as Go templates.
+const cloudInitTemplateExt = ".tmpl"
+
+// cloudInitHelp documents the cloud-init user data features common to the
+// commands supporting the --cloud-init flag.
+const cloudInitHelp = `Cloud-init user data:
+
+The --cloud-init flag can be specified multiple times, in which case the files
+are composed into a MIME multi-part archive (e.g. a #cloud-config file and
+shell scripts). Files having the "` + cloudInitTemplateExt + `" extension are rendered as Go
+templates, receiving the name of the instance (or of the Instance Pool) as
+{{ .Name }}, the zone as {{ .Zone }} and the values set using --cloud-init-var
+as {{ .Vars.KEY }}. #cloud-config files are validated locally before being
+submitted. Example:
+
+    exo compute instance create web --cloud-init base.yaml \
+        --cloud-init nginx.yaml.tmpl --cloud-init-var domain=example.net`
+
+// cloudInitTemplateData represents the data available to cloud-init user data
+// templates.
+type cloudInitTemplateData struct {
+	Name string
+	Zone string
+	Vars map[string]string
+}
+
+// cloudInitContentTypes maps cloud-init user data format markers (i.e. first
+// line prefixes) to MIME content types, as documented in
+// https://cloudinit.readthedocs.io/en/latest/explanation/format.html
+var cloudInitContentTypes = []struct {
+	prefix      string
+	contentType string
+}{
+	{"#cloud-config-archive", "text/cloud-config-archive"},
+	{"#cloud-config", "text/cloud-config"},
+	{"#cloud-boothook", "text/cloud-boothook"},
+	{"#include", "text/x-include-url"},
+	{"#part-handler", "text/part-handler"},
+	{"## template: jinja", "text/jinja2"},
+	{"#!", "text/x-shellscript"},
+}
+
+// cloudConfigKeyKinds lists the commonly used #cloud-config top-level keys,
+// associated with the YAML node kinds accepted for their values.
+var cloudConfigKeyKinds = map[string][]yaml.Kind{
+	"apt":                        {yaml.MappingNode},
+	"bootcmd":                    {yaml.SequenceNode},
+	"ca_certs":                   {yaml.MappingNode},
+	"chpasswd":                   {yaml.MappingNode},
+	"disable_root":               {yaml.ScalarNode},
+	"disk_setup":                 {yaml.MappingNode},
+	"final_message":              {yaml.ScalarNode},
+	"fqdn":                       {yaml.ScalarNode},
+	"fs_setup":                   {yaml.SequenceNode},
+	"groups":                     {yaml.ScalarNode, yaml.SequenceNode, yaml.MappingNode},
+	"hostname":                   {yaml.ScalarNode},
+	"locale":                     {yaml.ScalarNode},
+	"manage_etc_hosts":           {yaml.ScalarNode},
+	"mounts":                     {yaml.SequenceNode},
+	"ntp":                        {yaml.MappingNode},
+	"package_reboot_if_required": {yaml.ScalarNode},
+	"package_update":             {yaml.ScalarNode},
+	"package_upgrade":            {yaml.ScalarNode},
+	"packages":                   {yaml.SequenceNode},
+	"password":                   {yaml.ScalarNode},
+	"power_state":                {yaml.MappingNode},
+	"preserve_hostname":          {yaml.ScalarNode},
+	"runcmd":                     {yaml.SequenceNode},
+	"snap":                       {yaml.MappingNode},
+	"ssh_authorized_keys":        {yaml.SequenceNode},
+	"ssh_pwauth":                 {yaml.ScalarNode},
+	"swap":                       {yaml.MappingNode},
+	"timezone":                   {yaml.ScalarNode},
+	"users":                      {yaml.ScalarNode, yaml.SequenceNode, yaml.MappingNode},
+	"write_files":                {yaml.SequenceNode},
+	"yum_repos":                  {yaml.MappingNode},
+}
+
+// cloudInitContentType returns the MIME content type of cloud-init user data
+// based on its format marker, or an empty string if unknown.
+func cloudInitContentType(data []byte) string {
+	for _, t := range cloudInitContentTypes {
+		if bytes.HasPrefix(data, []byte(t.prefix)) {
+			return t.contentType
+		}
+	}
+
+	return ""
+}
+
+// validateCloudConfig checks that #cloud-config user data is valid YAML, and
+// that the commonly used top-level keys have values of the expected type.
+// Unknown top-level keys are reported as warnings.
+func validateCloudConfig(name string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: invalid YAML: %w", name, err)
+	}
+
+	// Empty document
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: line %d: cloud-config must be a YAML mapping", name, root.Line)
+	}
+
+	kindNames := map[yaml.Kind]string{
+		yaml.MappingNode:  "a mapping",
+		yaml.SequenceNode: "a list",
+		yaml.ScalarNode:   "a scalar value",
+	}
+
+	unknown := make([]string, 0)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		kinds, ok := cloudConfigKeyKinds[key.Value]
+		if !ok {
+			unknown = append(unknown, key.Value)
+			continue
+		}
+
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		// Allow null values (e.g. "packages:").
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+			continue
+		}
+
+		valid := false
+		for _, kind := range kinds {
+			if value.Kind == kind {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			expected := make([]string, len(kinds))
+			for j, kind := range kinds {
+				expected[j] = kindNames[kind]
+			}
+			return fmt.Errorf("%s: line %d: %q must be %s",
+				name, value.Line, key.Value, strings.Join(expected, " or "))
+		}
+
+		if key.Value == "write_files" {
+			for _, f := range value.Content {
+				if f.Kind != yaml.MappingNode {
+					return fmt.Errorf("%s: line %d: write_files entries must be mappings", name, f.Line)
+				}
+				hasPath := false
+				for j := 0; j+1 < len(f.Content); j += 2 {
+					if f.Content[j].Value == "path" {
+						hasPath = true
+					}
+				}
+				if !hasPath {
+					return fmt.Errorf("%s: line %d: write_files entry is missing a \"path\"", name, f.Line)
+				}
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fmt.Fprintf(os.Stderr, "warning: %s: unknown cloud-config keys: %s\n", name, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// cloudInitPart represents a cloud-init user data part.
+type cloudInitPart struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+// readCloudInitPart reads a cloud-init user data file, rendering it using the
+// template data if the file has the cloudInitTemplateExt extension, and
+// validates its content.
+func readCloudInitPart(path string, data *cloudInitTemplateData) (*cloudInitPart, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+
+	if strings.HasSuffix(name, cloudInitTemplateExt) {
+		tpl, err := template.New(name).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid template: %w", name, err)
+		}
+
+		buf := bytes.NewBuffer(nil)
+		if err := tpl.Execute(buf, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		content = buf.Bytes()
+		name = strings.TrimSuffix(name, cloudInitTemplateExt)
+	}
+
+	contentType := cloudInitContentType(content)
+	if contentType == "text/cloud-config" {
+		if err := validateCloudConfig(name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cloudInitPart{name: name, contentType: contentType, content: content}, nil
+}
+
+// composeCloudInitMultipart returns a MIME multi-part archive of the
+// specified cloud-init user data parts.
+func composeCloudInitMultipart(parts []*cloudInitPart) ([]byte, error) {
+	body := bytes.NewBuffer(nil)
+	mw := multipart.NewWriter(body)
+
+	// Using a boundary derived from the parts content instead of a random one,
+	// so that the resulting user data are reproducible (e.g. to be diffed).
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00", part.name, part.content)
+	}
+	if err := mw.SetBoundary(hex.EncodeToString(h.Sum(nil))); err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		if part.contentType == "" {
+			return nil, fmt.Errorf(
+				"%s: unable to determine user data format (expected first line to start with %q or %q)",
+				part.name,
+				"#cloud-config",
+				"#!",
+			)
+		}
+
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="utf-8"`},
+			"Mime-Version":              {"1.0"},
+			"Content-Transfer-Encoding": {"7bit"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", part.name)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write(part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	out := bytes.NewBuffer(nil)
+	_, _ = fmt.Fprintf(out, "Content-Type: multipart/mixed; boundary=%q\n", mw.Boundary())
+	_, _ = fmt.Fprintf(out, "MIME-Version: 1.0\n\n")
+	_, _ = out.Write(body.Bytes())
+
+	return out.Bytes(), nil
+}
+
+// renderUserData returns the cloud-init user data built from the specified
+// files: files having the cloudInitTemplateExt extension are rendered as Go
+// templates using the data specified, and multiple files are composed into a
+// MIME multi-part archive.
+func renderUserData(paths []string, data *cloudInitTemplateData) ([]byte, error) {
+	hasTemplate := false
+	for _, path := range paths {
+		if strings.HasSuffix(path, cloudInitTemplateExt) {
+			hasTemplate = true
+		}
+	}
+	if len(data.Vars) > 0 && !hasTemplate {
+		return nil, fmt.Errorf(
+			"cloud-init variables specified but no template file (%s extension) provided",
+			cloudInitTemplateExt,
+		)
+	}
+
+	parts := make([]*cloudInitPart, len(paths))
+	for i, path := range paths {
+		part, err := readCloudInitPart(path, data)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = part
+	}
+
+	if len(parts) == 1 {
+		return parts[0].content, nil
+	}
+
+	return composeCloudInitMultipart(parts)
+}
+
+// buildUserData returns the encoded cloud-init user data rendered from the
+// specified files (see renderUserData()).
+func buildUserData(paths []string, data *cloudInitTemplateData, compress bool) (string, error) {
+	content, err := renderUserData(paths, data)
+	if err != nil {
+		return "", err
+	}
+
+	userData, err := encodeUserData(content, compress)
+	if err != nil {
+		return "", err
+	}
+
+	if len(userData) >= maxUserDataLength {
+		return "", fmt.Errorf("user-data maximum allowed length is %d bytes", maxUserDataLength)
+	}
+
+	return userData, nil
+}
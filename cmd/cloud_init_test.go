@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateCloudConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			data: `#cloud-config
+package_update: true
+packages:
+  - nginx
+write_files:
+  - path: /etc/motd
+    content: hello
+runcmd:
+`,
+		},
+		{
+			name: "users and groups alternative forms",
+			data: "#cloud-config\nusers: default\ngroups:\n  admins: [alice, bob]\n",
+		},
+		{
+			name:    "invalid YAML",
+			data:    "#cloud-config\npackages: [nginx\n",
+			wantErr: true,
+		},
+		{
+			name:    "not a mapping",
+			data:    "#cloud-config\n- nginx\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid key type",
+			data:    "#cloud-config\npackages: nginx\n",
+			wantErr: true,
+		},
+		{
+			name:    "write_files entry without path",
+			data:    "#cloud-config\nwrite_files:\n  - content: hello\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCloudConfig("test", []byte(tt.data))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_composeCloudInitMultipart(t *testing.T) {
+	out, err := composeCloudInitMultipart([]*cloudInitPart{
+		{name: "a.yaml", contentType: cloudInitContentType([]byte("#cloud-config\n")), content: []byte("#cloud-config\n")},
+		{name: "b.sh", contentType: cloudInitContentType([]byte("#!/bin/sh\n")), content: []byte("#!/bin/sh\n")},
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(out), "Content-Type: multipart/mixed; boundary=")
+	require.Contains(t, string(out), `Content-Type: text/cloud-config; charset="utf-8"`)
+	require.Contains(t, string(out), `Content-Type: text/x-shellscript; charset="utf-8"`)
+
+	_, err = composeCloudInitMultipart([]*cloudInitPart{
+		{name: "a.txt", content: []byte("hello\n")},
+	})
+	require.Error(t, err)
+}
@@ -262,6 +262,9 @@ func cliCommandFlagSet(c cliCommand) (*pflag.FlagSet, error) {
 		case reflect.Int64:
 			fs.Int64P(flagName, flagShort, flagDefaultValue.(int64), flagUsage)
 
+		case reflect.Float64:
+			fs.Float64P(flagName, flagShort, flagDefaultValue.(float64), flagUsage)
+
 		case reflect.Bool:
 			fs.BoolP(flagName, flagShort, flagDefaultValue.(bool), flagUsage)
 
@@ -507,6 +510,13 @@ func cliCommandDefaultPreRun(c cliCommand, cmd *cobra.Command, args []string) er
 			}
 			cField.SetInt(v)
 
+		case reflect.Float64:
+			v, err := cmd.Flags().GetFloat64(flagName)
+			if err != nil {
+				return fmt.Errorf("error retrieving value for flag --%s: %s", flagName, err)
+			}
+			cField.SetFloat(v)
+
 		case reflect.Bool:
 			v, err := cmd.Flags().GetBool(flagName)
 			if err != nil {
@@ -570,7 +580,18 @@ func registerCLICommand(parent *cobra.Command, c cliCommand) error {
 		Short:   c.cmdShort(),
 		Long:    c.cmdLong(),
 		PreRunE: c.cmdPreRun,
-		RunE:    c.cmdRun,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if multiAccountMode() {
+				mc, ok := c.(cliCommandMultiAccount)
+				if !ok {
+					return fmt.Errorf("command %q doesn't support multiple accounts", cmd.CommandPath())
+				}
+
+				return cmdRunMultiAccount(mc)
+			}
+
+			return c.cmdRun(cmd, args)
+		},
 	}
 
 	cmdFlags, err := cliCommandFlagSet(c)
@@ -15,8 +15,9 @@ type testCLICmd struct {
 	RequiredArg  string   `cli-arg:"#"`
 	OptionalArgs []string `cli-arg:"?" cli-usage:"OPTION"`
 
-	SingleString string `cli-short:"s"`
-	Int64        int64  `cli-flag:"int64" cli-short:"i"`
+	SingleString string  `cli-short:"s"`
+	Int64        int64   `cli-flag:"int64" cli-short:"i"`
+	Float64      float64 `cli-flag:"float64"`
 	Bool         bool
 	MultiStrings []string `cli-flag:"multi-string-value" cli-usage:"multiple strings"`
 	StringsMap   map[string]string
@@ -50,6 +51,7 @@ func Test_cliCommandFlagSet(t *testing.T) {
 	var (
 		testSingleStringValue       = "test"
 		testInt64Value        int64 = 42
+		testFloat64Value            = 0.42
 		testBoolValue               = true
 		testMultiStringsValue       = []string{"a", "b", "c"}
 		testStringsMap              = map[string]string{"k1": "v1", "k2": "v2"}
@@ -58,6 +60,7 @@ func Test_cliCommandFlagSet(t *testing.T) {
 	cmd := &testCLICmd{
 		SingleString: testSingleStringValue,
 		Int64:        testInt64Value,
+		Float64:      testFloat64Value,
 		Bool:         testBoolValue,
 		MultiStrings: testMultiStringsValue,
 		StringsMap:   testStringsMap,
@@ -66,6 +69,7 @@ func Test_cliCommandFlagSet(t *testing.T) {
 	expected := pflag.NewFlagSet("", pflag.ExitOnError)
 	expected.StringP("single-string", "s", testSingleStringValue, "")
 	expected.Int64P("int64", "i", testInt64Value, "")
+	expected.Float64P("float64", "", testFloat64Value, "")
 	expected.BoolP("bool", "", testBoolValue, "")
 	expected.StringSliceP("multi-string-value", "", testMultiStringsValue, "multiple strings")
 	expected.StringToStringP("strings-map", "", testStringsMap, "")
@@ -103,6 +107,7 @@ func Test_cliCommandDefaultPreRun(t *testing.T) {
 		testOptionalArgs            = []string{"optional-arg1", "optional-arg2"}
 		testSingleStringValue       = "test"
 		testInt64Value        int64 = 42
+		testFloat64Value            = 0.42
 		testBoolValue               = true
 		testMultiStringsValue       = []string{"a", "b", "c"}
 		testStringsMap              = map[string]string{"k1": "v1", "k2": "v2"}
@@ -111,6 +116,7 @@ func Test_cliCommandDefaultPreRun(t *testing.T) {
 	testFlags := pflag.NewFlagSet("", pflag.ExitOnError)
 	testFlags.StringP("single-string", "s", "", "")
 	testFlags.Int64P("int64", "i", 0, "")
+	testFlags.Float64P("float64", "", 0, "")
 	testFlags.BoolP("bool", "", false, "")
 	testFlags.StringSliceP("multi-string-value", "", nil, "multiple strings")
 	testFlags.StringToStringP("strings-map", "", nil, "")
@@ -179,6 +185,7 @@ func Test_cliCommandDefaultPreRun(t *testing.T) {
 					flags := pflag.NewFlagSet("", pflag.ExitOnError)
 					flags.StringP("single-string", "s", testSingleStringValue, "")
 					flags.Int64P("int64", "i", testInt64Value, "")
+					flags.Float64P("float64", "", testFloat64Value, "")
 					flags.BoolP("bool", "", testBoolValue, "")
 					flags.StringSliceP("multi-string-value", "", testMultiStringsValue, "")
 					flags.StringToStringP("strings-map", "", testStringsMap, "")
@@ -193,6 +200,7 @@ func Test_cliCommandDefaultPreRun(t *testing.T) {
 				RequiredArg:  testRequiredArg,
 				SingleString: testSingleStringValue,
 				Int64:        testInt64Value,
+				Float64:      testFloat64Value,
 				Bool:         testBoolValue,
 				MultiStrings: testMultiStringsValue,
 				StringsMap:   testStringsMap,
@@ -224,6 +232,7 @@ func Test_registerCobraCommand(t *testing.T) {
 		testOptionalArgs            = []string{"optional-arg1", "optional-arg2"}
 		testSingleStringValue       = "test"
 		testInt64Value        int64 = 42
+		testFloat64Value            = 0.42
 		testBoolValue               = true
 		testMultiStringsValue       = []string{"a", "b", "c"}
 		testStringsMap              = map[string]string{"k1": "v1", "k2": "v2"}
@@ -254,6 +263,7 @@ func Test_registerCobraCommand(t *testing.T) {
 		require.Equal(t, testOptionalArgs, testCmd.OptionalArgs)
 		require.Equal(t, testSingleStringValue, testCmd.SingleString)
 		require.Equal(t, testInt64Value, testCmd.Int64)
+		require.Equal(t, testFloat64Value, testCmd.Float64)
 		require.Equal(t, testBoolValue, testCmd.Bool)
 		require.Equal(t, testMultiStringsValue, testCmd.MultiStrings)
 		require.Equal(t, testStringsMap, testCmd.StringsMap)
@@ -275,6 +285,7 @@ func Test_registerCobraCommand(t *testing.T) {
 		"test",
 		"--single-string=" + testSingleStringValue,
 		"--int64=" + fmt.Sprint(testInt64Value),
+		"--float64=" + fmt.Sprint(testFloat64Value),
 		"--bool=" + fmt.Sprint(testBoolValue),
 		"--multi-string-value=a",
 		"--multi-string-value=b",
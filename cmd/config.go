@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/exoscale/egoscale"
@@ -39,6 +40,13 @@ type account struct {
 	DefaultOutputFormat  string
 	ClientTimeout        int
 	CustomHeaders        map[string]string
+
+	// Network settings
+	Proxy                 string
+	CACertificateFile     string
+	ClientCertificateFile string
+	ClientKeyFile         string
+	TLSMinVersion         string
 }
 
 func (a account) APISecret() string {
@@ -56,6 +64,57 @@ func (a account) APISecret() string {
 	return a.Secret
 }
 
+// setDefaults sets the default values of the account settings not
+// specified in the configuration file.
+func (a *account) setDefaults() {
+	if a.Endpoint == "" {
+		if a.ComputeEndpoint != "" {
+			a.Endpoint = a.ComputeEndpoint
+		} else {
+			a.Endpoint = defaultEndpoint
+		}
+	}
+
+	if a.Environment == "" {
+		a.Environment = defaultEnvironment
+	}
+
+	if a.DefaultZone == "" {
+		a.DefaultZone = defaultZone
+	}
+
+	if a.DNSEndpoint == "" {
+		a.DNSEndpoint = buildDNSAPIEndpoint(a.Endpoint)
+	}
+
+	if a.DefaultTemplate == "" {
+		a.DefaultTemplate = defaultTemplate
+	}
+
+	if a.SosEndpoint == "" {
+		a.SosEndpoint = defaultSosEndpoint
+	}
+
+	if a.RunstatusEndpoint == "" {
+		a.RunstatusEndpoint = defaultRunstatusEndpoint
+	}
+
+	if a.ClientTimeout == 0 {
+		a.ClientTimeout = defaultClientTimeout
+	}
+	clientTimeoutFromEnv := readFromEnv("EXOSCALE_API_TIMEOUT")
+	if clientTimeoutFromEnv != "" {
+		if t, err := strconv.Atoi(clientTimeoutFromEnv); err == nil {
+			a.ClientTimeout = t
+		}
+	}
+
+	a.Endpoint = strings.TrimRight(a.Endpoint, "/")
+	a.DNSEndpoint = strings.TrimRight(a.DNSEndpoint, "/")
+	a.SosEndpoint = strings.TrimRight(a.SosEndpoint, "/")
+	a.RunstatusEndpoint = strings.TrimRight(a.RunstatusEndpoint, "/")
+}
+
 func (a account) AccountName() string {
 	if a.Name == "" {
 		resp, err := cs.GetWithContext(gContext, egoscale.Account{})
@@ -187,6 +246,21 @@ func saveConfig(filePath string, newAccounts *config) error {
 		if acc.DefaultTemplate != "" {
 			accounts[i]["defaultTemplate"] = acc.DefaultTemplate
 		}
+		if acc.Proxy != "" {
+			accounts[i]["proxy"] = acc.Proxy
+		}
+		if acc.CACertificateFile != "" {
+			accounts[i]["caCertificateFile"] = acc.CACertificateFile
+		}
+		if acc.ClientCertificateFile != "" {
+			accounts[i]["clientCertificateFile"] = acc.ClientCertificateFile
+		}
+		if acc.ClientKeyFile != "" {
+			accounts[i]["clientKeyFile"] = acc.ClientKeyFile
+		}
+		if acc.TLSMinVersion != "" {
+			accounts[i]["tlsMinVersion"] = acc.TLSMinVersion
+		}
 		if len(acc.SecretCommand) != 0 {
 			accounts[i]["secretCommand"] = acc.SecretCommand
 		} else {
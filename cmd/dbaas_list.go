@@ -51,7 +51,7 @@ func (c *dbaasServiceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(cs.Client, gCurrentAccount)
 	}
 
 	out := make(dbaasServiceListOutput, 0)
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+)
+
+const fcrdnsStatusOK = "ok"
+
+// fcrdnsTarget represents a resource owning public IP addresses (i.e. a
+// Compute instance or an Elastic IP) for which forward DNS records (A/AAAA)
+// and reverse DNS are managed together, in order to pass Forward-Confirmed
+// reverse DNS (FCrDNS) checks.
+type fcrdnsTarget struct {
+	id        string
+	name      string
+	zone      string
+	addresses []net.IP
+
+	getReverseDNS    func(ctx context.Context) (string, error)
+	updateReverseDNS func(ctx context.Context, fqdn string) error
+	deleteReverseDNS func(ctx context.Context) error
+}
+
+type fcrdnsCheckItemOutput struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Zone       string `json:"zone"`
+	ReverseDNS string `json:"reverse_dns" outputLabel:"Reverse DNS"`
+	Status     string `json:"status"`
+}
+
+func (o *fcrdnsCheckItemOutput) toJSON()  { outputJSON(o) }
+func (o *fcrdnsCheckItemOutput) toText()  { outputText(o) }
+func (o *fcrdnsCheckItemOutput) toTable() { outputTable(o) }
+
+type fcrdnsCheckOutput []fcrdnsCheckItemOutput
+
+func (o *fcrdnsCheckOutput) toJSON()  { outputJSON(o) }
+func (o *fcrdnsCheckOutput) toText()  { outputText(o) }
+func (o *fcrdnsCheckOutput) toTable() { outputTable(o) }
+
+// dnsRecordTypeForIP returns the DNS record type matching the IP address
+// family ("A" for IPv4, "AAAA" for IPv6).
+func dnsRecordTypeForIP(ip net.IP) string {
+	if ip.To4() != nil {
+		return "A"
+	}
+	return "AAAA"
+}
+
+// normalizeFQDN returns the specified domain name lower-cased and without
+// trailing dot.
+func normalizeFQDN(fqdn string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(fqdn), "."))
+}
+
+// findDNSDomainForFQDN returns the DNS domain (among the specified domains)
+// the FQDN belongs to, and the name of the record relative to the domain. If
+// several domains match, the most specific one is returned.
+func findDNSDomainForFQDN(domains []exov2.DNSDomain, fqdn string) (*exov2.DNSDomain, string) {
+	var (
+		found      *exov2.DNSDomain
+		recordName string
+	)
+
+	fqdn = normalizeFQDN(fqdn)
+	for i := range domains {
+		name := normalizeFQDN(*domains[i].UnicodeName)
+
+		switch {
+		case fqdn == name:
+			return &domains[i], ""
+
+		case strings.HasSuffix(fqdn, "."+name):
+			if found == nil || len(name) > len(*found.UnicodeName) {
+				found = &domains[i]
+				recordName = strings.TrimSuffix(fqdn, "."+name)
+			}
+		}
+	}
+
+	return found, recordName
+}
+
+// findDNSDomain returns the DNS domain matching the specified name.
+func findDNSDomain(ctx context.Context, zone, name string) (*exov2.DNSDomain, error) {
+	domains, err := cs.ListDNSDomains(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list DNS domains: %w", err)
+	}
+
+	for i := range domains {
+		if normalizeFQDN(*domains[i].UnicodeName) == normalizeFQDN(name) {
+			return &domains[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("DNS domain %q not found", name)
+}
+
+// fcrdnsRegister creates (or updates) the A/AAAA records NAME.DOMAIN
+// pointing to the target IP addresses, and sets the target reverse DNS to the
+// resulting FQDN.
+func fcrdnsRegister(ctx context.Context, t *fcrdnsTarget, domain, name string, ttl int64) error {
+	if len(t.addresses) == 0 {
+		return fmt.Errorf("%s has no public IP address", t.name)
+	}
+
+	dnsDomain, err := findDNSDomain(ctx, t.zone, domain)
+	if err != nil {
+		return err
+	}
+
+	records, err := cs.ListDNSDomainRecords(ctx, t.zone, *dnsDomain.ID)
+	if err != nil {
+		return fmt.Errorf("unable to list DNS domain records: %w", err)
+	}
+
+	for _, ip := range t.addresses {
+		var (
+			recordType = dnsRecordTypeForIP(ip)
+			content    = ip.String()
+			existing   = make([]*exov2.DNSDomainRecord, 0)
+		)
+
+		for i := range records {
+			if *records[i].Type == recordType && normalizeFQDN(*records[i].Name) == normalizeFQDN(name) {
+				existing = append(existing, &records[i])
+			}
+		}
+
+		if len(existing) > 0 {
+			// Only one record of each type must remain for the FQDN to
+			// resolve to the target address only: update the first one and
+			// delete the others.
+			record := existing[0]
+			record.Content = &content
+			record.TTL = &ttl
+			if err = cs.UpdateDNSDomainRecord(ctx, t.zone, *dnsDomain.ID, record); err != nil {
+				return fmt.Errorf("unable to update DNS %s record: %w", recordType, err)
+			}
+
+			for _, extra := range existing[1:] {
+				if err = cs.DeleteDNSDomainRecord(ctx, t.zone, *dnsDomain.ID, extra); err != nil {
+					return fmt.Errorf("unable to delete DNS %s record %s: %w", recordType, *extra.ID, err)
+				}
+			}
+			continue
+		}
+
+		if _, err = cs.CreateDNSDomainRecord(ctx, t.zone, *dnsDomain.ID, &exov2.DNSDomainRecord{
+			Content: &content,
+			Name:    &name,
+			TTL:     &ttl,
+			Type:    &recordType,
+		}); err != nil {
+			return fmt.Errorf("unable to create DNS %s record: %w", recordType, err)
+		}
+	}
+
+	fqdn := normalizeFQDN(*dnsDomain.UnicodeName)
+	if name != "" {
+		fqdn = name + "." + fqdn
+	}
+
+	if err = t.updateReverseDNS(ctx, fqdn); err != nil {
+		return fmt.Errorf("unable to update reverse DNS: %w", err)
+	}
+
+	return nil
+}
+
+// fcrdnsUnregister deletes the A/AAAA records pointing to the target IP
+// addresses matching the target reverse DNS, then deletes the reverse DNS.
+func fcrdnsUnregister(ctx context.Context, t *fcrdnsTarget) error {
+	rdns, err := t.getReverseDNS(ctx)
+	if err != nil && !errors.Is(err, exoapi.ErrNotFound) {
+		return fmt.Errorf("unable to retrieve reverse DNS: %w", err)
+	}
+	if rdns == "" {
+		return fmt.Errorf("%s has no reverse DNS set", t.name)
+	}
+
+	domains, err := cs.ListDNSDomains(ctx, t.zone)
+	if err != nil {
+		return fmt.Errorf("unable to list DNS domains: %w", err)
+	}
+
+	if dnsDomain, name := findDNSDomainForFQDN(domains, rdns); dnsDomain != nil {
+		records, err := cs.ListDNSDomainRecords(ctx, t.zone, *dnsDomain.ID)
+		if err != nil {
+			return fmt.Errorf("unable to list DNS domain records: %w", err)
+		}
+
+		for i := range records {
+			if normalizeFQDN(*records[i].Name) != name {
+				continue
+			}
+
+			for _, ip := range t.addresses {
+				if *records[i].Type == dnsRecordTypeForIP(ip) && net.ParseIP(*records[i].Content).Equal(ip) {
+					if err := cs.DeleteDNSDomainRecord(ctx, t.zone, *dnsDomain.ID, &records[i]); err != nil {
+						return fmt.Errorf("unable to delete DNS %s record: %w", *records[i].Type, err)
+					}
+				}
+			}
+		}
+	}
+
+	if err := t.deleteReverseDNS(ctx); err != nil {
+		return fmt.Errorf("unable to delete reverse DNS: %w", err)
+	}
+
+	return nil
+}
+
+// fcrdnsCheck verifies that the reverse DNS of the target resolves (using the
+// DNS domains managed in Exoscale DNS) to each of its IP addresses. Domain
+// records are retrieved through the cache specified, as it is intended to
+// be shared across multiple checks.
+func fcrdnsCheck(
+	ctx context.Context,
+	t *fcrdnsTarget,
+	domains []exov2.DNSDomain,
+	cache *resourceCache,
+) (*fcrdnsCheckItemOutput, error) {
+	out := fcrdnsCheckItemOutput{
+		ID:   t.id,
+		Name: t.name,
+		Zone: t.zone,
+	}
+
+	rdns, err := t.getReverseDNS(ctx)
+	if err != nil && !errors.Is(err, exoapi.ErrNotFound) {
+		return nil, fmt.Errorf("unable to retrieve reverse DNS: %w", err)
+	}
+	out.ReverseDNS = normalizeFQDN(rdns)
+
+	if out.ReverseDNS == "" {
+		out.Status = "no reverse DNS"
+		return &out, nil
+	}
+
+	dnsDomain, name := findDNSDomainForFQDN(domains, out.ReverseDNS)
+	if dnsDomain == nil {
+		out.Status = "domain not managed in Exoscale DNS"
+		return &out, nil
+	}
+
+	v, err := cache.get("dns-domain-records/"+*dnsDomain.ID, func() (interface{}, error) {
+		return cs.ListDNSDomainRecords(ctx, t.zone, *dnsDomain.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list DNS domain records: %w", err)
+	}
+	records := v.([]exov2.DNSDomainRecord)
+
+	problems := make([]string, 0)
+	for _, ip := range t.addresses {
+		recordType := dnsRecordTypeForIP(ip)
+
+		contents := make([]string, 0)
+		for _, r := range records {
+			if *r.Type == recordType && normalizeFQDN(*r.Name) == name {
+				contents = append(contents, *r.Content)
+			}
+		}
+
+		switch {
+		case len(contents) == 0:
+			problems = append(problems, fmt.Sprintf("%s record missing", recordType))
+
+		case !func() bool {
+			for _, c := range contents {
+				if net.ParseIP(c).Equal(ip) {
+					return true
+				}
+			}
+			return false
+		}():
+			problems = append(problems, fmt.Sprintf("%s record mismatch (%s != %s)",
+				recordType, strings.Join(contents, ","), ip))
+		}
+	}
+
+	out.Status = fcrdnsStatusOK
+	if len(problems) > 0 {
+		out.Status = strings.Join(problems, ", ")
+	}
+
+	return &out, nil
+}
+
+// fcrdnsCheckAll checks the forward and reverse DNS consistency of all the
+// targets returned by the list function for each of the specified zones.
+// Errors occurring during listing are reported as a warning, as the results
+// are still relevant.
+func fcrdnsCheckAll(
+	zones []string,
+	list func(ctx context.Context, zone string) ([]*fcrdnsTarget, error),
+) (fcrdnsCheckOutput, error) {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zones[0]))
+	domains, err := cs.ListDNSDomains(ctx, zones[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to list DNS domains: %w", err)
+	}
+
+	var (
+		out   = make(fcrdnsCheckOutput, 0)
+		mu    sync.Mutex
+		cache = newResourceCache()
+	)
+
+	err = forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		targets, err := list(ctx, zone)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range targets {
+			res, err := fcrdnsCheck(ctx, t, domains, cache)
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+
+			mu.Lock()
+			out = append(out, *res)
+			mu.Unlock()
+		}
+
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during listing, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Zone != out[j].Zone {
+			return out[i].Zone < out[j].Zone
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
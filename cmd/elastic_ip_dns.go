@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"net"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/spf13/cobra"
+)
+
+var elasticIPDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage Elastic IPs forward and reverse DNS records",
+	Long: `These commands manage the forward DNS records (A/AAAA) of Elastic IPs together
+with their reverse DNS, so that they pass Forward-Confirmed reverse DNS
+(FCrDNS) checks: "register" creates the records in Exoscale DNS and sets the
+reverse DNS to the same FQDN, "unregister" removes both, and "register
+--check" reports the mismatches across all the Elastic IPs of the account.`,
+}
+
+func init() {
+	elasticIPCmd.AddCommand(elasticIPDNSCmd)
+}
+
+// newElasticIPFCrDNSTarget returns a fcrdnsTarget for the specified Elastic
+// IP.
+func newElasticIPFCrDNSTarget(elasticIP *exov2.ElasticIP, zone string) *fcrdnsTarget {
+	addresses := make([]net.IP, 0)
+	if elasticIP.IPAddress != nil {
+		addresses = append(addresses, *elasticIP.IPAddress)
+	}
+
+	return &fcrdnsTarget{
+		id:        *elasticIP.ID,
+		name:      elasticIP.IPAddress.String(),
+		zone:      zone,
+		addresses: addresses,
+
+		getReverseDNS: func(ctx context.Context) (string, error) {
+			return cs.GetElasticIPReverseDNS(ctx, zone, *elasticIP.ID)
+		},
+		updateReverseDNS: func(ctx context.Context, fqdn string) error {
+			return cs.UpdateElasticIPReverseDNS(ctx, zone, *elasticIP.ID, fqdn)
+		},
+		deleteReverseDNS: func(ctx context.Context) error {
+			return cs.DeleteElasticIPReverseDNS(ctx, zone, *elasticIP.ID)
+		},
+	}
+}
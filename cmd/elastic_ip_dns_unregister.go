@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type elasticIPDNSUnregisterCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"unregister"`
+
+	ElasticIP string `cli-arg:"#" cli-usage:"IP-ADDRESS|ID"`
+
+	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Zone  string `cli-short:"z" cli-usage:"Elastic IP zone"`
+}
+
+func (c *elasticIPDNSUnregisterCmd) cmdAliases() []string { return nil }
+
+func (c *elasticIPDNSUnregisterCmd) cmdShort() string {
+	return "Unregister an Elastic IP from DNS"
+}
+
+func (c *elasticIPDNSUnregisterCmd) cmdLong() string {
+	return `This command deletes the DNS A/AAAA records matching the Elastic IP
+reverse DNS and pointing to its address, then deletes the Elastic IP reverse
+DNS.`
+}
+
+func (c *elasticIPDNSUnregisterCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *elasticIPDNSUnregisterCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	elasticIP, err := cs.FindElasticIP(ctx, c.Zone, c.ElasticIP)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	if !c.Force {
+		if !askQuestion(fmt.Sprintf("Are you sure you want to unregister Elastic IP %q from DNS?", c.ElasticIP)) {
+			return nil
+		}
+	}
+
+	decorateAsyncOperation(fmt.Sprintf("Unregistering Elastic IP %q from DNS...", c.ElasticIP), func() {
+		err = fcrdnsUnregister(ctx, newElasticIPFCrDNSTarget(elasticIP, c.Zone))
+	})
+
+	return err
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(elasticIPDNSCmd, &elasticIPDNSUnregisterCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
+)
+
+// instanceLabels returns the labels of a Compute instance, or nil if it has
+// none.
+func instanceLabels(instance *exov2.Instance) map[string]string {
+	if instance.Labels == nil {
+		return nil
+	}
+	return *instance.Labels
+}
+
+// findInstancesBySelector returns the Compute instances of the specified
+// zone matching the label selector expression.
+func findInstancesBySelector(ctx context.Context, zone, selector string) ([]*exov2.Instance, error) {
+	s, err := parseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := cs.ListInstances(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Compute instances in zone %s: %w", zone, err)
+	}
+
+	instances := make([]*exov2.Instance, 0)
+	for _, instance := range list {
+		if s.matches(instanceLabels(instance)) {
+			instances = append(instances, instance)
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no Compute instance matching selector %q found in zone %q", selector, zone)
+	}
+
+	return instances, nil
+}
+
+// askBulkInstanceQuestion lists the Compute instances targeted by a bulk
+// operation and asks the user for confirmation.
+func askBulkInstanceQuestion(action string, instances []*exov2.Instance) bool {
+	fmt.Fprintf(os.Stderr, "The following %d instances will be affected:\n", len(instances))
+	for _, instance := range instances {
+		fmt.Fprintf(os.Stderr, "  - %s (%s)\n", *instance.Name, *instance.ID)
+	}
+
+	return askQuestion(fmt.Sprintf("Are you sure you want to %s these %d instances?", action, len(instances)))
+}
+
+// bulkInstanceOperation executes the function f on each of the specified
+// Compute instances in parallel (bounded by the "--parallelism" global flag),
+// displaying a progress spinner per instance. It returns a multierror.Error
+// containing all errors that may have occurred during execution.
+func bulkInstanceOperation(message string, instances []*exov2.Instance, f func(*exov2.Instance) error) error {
+	p := mpb.NewWithContext(gContext,
+		mpb.WithOutput(os.Stderr),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+	)
+
+	bars := make([]*mpb.Bar, len(instances))
+	for i, instance := range instances {
+		name := fmt.Sprintf("%s %q...", message, *instance.Name)
+
+		bars[i] = p.AddSpinner(
+			1,
+			mpb.SpinnerOnLeft,
+			mpb.PrependDecorators(decor.Name(name, decor.WC{W: len(name) + 1, C: decor.DidentRight})),
+			mpb.AppendDecorators(decor.OnComplete(decor.Elapsed(decor.ET_STYLE_GO), "done")),
+		)
+	}
+
+	err := forEachParallel(len(instances), func(i int) error {
+		if err := f(instances[i]); err != nil {
+			bars[i].Abort(false)
+			return fmt.Errorf("instance %s: %w", *instances[i].Name, err)
+		}
+
+		bars[i].Increment(1)
+		return nil
+	})
+	p.Wait()
+
+	return err
+}
+
+// validateInstanceOrSelector checks that exactly one of a Compute instance
+// NAME|ID argument or a label selector has been specified.
+func validateInstanceOrSelector(instance, selector string) error {
+	if instance == "" && selector == "" {
+		return fmt.Errorf("missing arguments, run with --help for usage")
+	}
+
+	if instance != "" && selector != "" {
+		return fmt.Errorf("an instance NAME|ID and a label selector cannot be specified at the same time")
+	}
+
+	return nil
+}
+
+// findInstances returns the Compute instances of the specified zones matching
+// either one of the names/IDs, or the label selector expression specified. If
+// neither are specified, all the instances of the zones are returned.
+func findInstances(zones, names []string, selector string) ([]*exov2.Instance, error) {
+	var s labelSelector
+	if selector != "" {
+		var err error
+		if s, err = parseLabelSelector(selector); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		instances = make([]*exov2.Instance, 0)
+		found     = make(map[string]bool)
+		mu        sync.Mutex
+	)
+
+	err := forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+
+		list, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("unable to list Compute instances in zone %s: %w", zone, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, instance := range list {
+			switch {
+			case s != nil:
+				if !s.matches(instanceLabels(instance)) {
+					continue
+				}
+
+			case len(names) > 0:
+				matched := false
+				for _, name := range names {
+					if name == *instance.ID || name == *instance.Name {
+						found[name] = true
+						matched = true
+					}
+				}
+				if !matched {
+					continue
+				}
+			}
+
+			instances = append(instances, instance)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("instance %q not found", name)
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no matching instance found")
+	}
+
+	return instances, nil
+}
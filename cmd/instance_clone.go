@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+type instanceCloneCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"clone"`
+
+	Instance string `cli-arg:"#" cli-usage:"SOURCE-NAME|ID"`
+	Name     string `cli-arg:"#" cli-usage:"NAME"`
+
+	Cleanup      bool   `cli-usage:"delete the intermediate snapshot and template once the clone is created, or all intermediate resources on failure"`
+	InstanceType string `cli-usage:"clone instance type (format: [FAMILY.]SIZE, default: same as source)"`
+	SSHKey       string `cli-flag:"ssh-key" cli-usage:"SSH key to deploy on the clone (default: same as source)"`
+	Zone         string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceCloneCmd) cmdAliases() []string { return nil }
+
+func (c *instanceCloneCmd) cmdShort() string { return "Clone a Compute instance" }
+
+func (c *instanceCloneCmd) cmdLong() string {
+	return `This command creates a new Compute instance from a snapshot of the source
+instance disk, promoted to a private template. The clone is created with the
+same instance type, disk size, Security Groups, Private Networks, Anti-Affinity
+Groups and labels as the source instance.
+
+The intermediate snapshot and template are kept unless the --cleanup flag is
+set. If the clone creation fails, the intermediate resources created until
+then (including a partially configured clone) are deleted if the --cleanup
+flag is set, otherwise their IDs are reported in the error message.`
+}
+
+func (c *instanceCloneCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceCloneCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	// Snapshot creation can take a _long time_, raising
+	// the Exoscale API client timeout as a precaution.
+	cs.Client.SetTimeout(time.Hour)
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	source, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	sourceTemplate, err := cs.GetTemplate(ctx, c.Zone, *source.TemplateID)
+	if err != nil {
+		return fmt.Errorf("error retrieving source instance template: %w", err)
+	}
+
+	clone := &egoscale.Instance{
+		AntiAffinityGroupIDs: source.AntiAffinityGroupIDs,
+		DeployTargetID:       source.DeployTargetID,
+		DiskSize:             source.DiskSize,
+		IPv6Enabled:          source.IPv6Enabled,
+		InstanceTypeID:       source.InstanceTypeID,
+		Labels:               source.Labels,
+		Name:                 &c.Name,
+		PublicIPAssignment:   source.PublicIPAssignment,
+		SecurityGroupIDs:     source.SecurityGroupIDs,
+		SSHKey:               utils.NonEmptyStringPtr(c.SSHKey),
+		UserData:             source.UserData,
+	}
+
+	if c.InstanceType != "" {
+		instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.InstanceType)
+		if err != nil {
+			return fmt.Errorf("error retrieving instance type: %w", err)
+		}
+		clone.InstanceTypeID = instanceType.ID
+	}
+
+	// The SSH key deployed on the source instance might have been deleted
+	// since (e.g. single-use SSH keys generated by "exo compute instance create").
+	if clone.SSHKey == nil && source.SSHKey != nil {
+		if _, err := cs.GetSSHKey(ctx, c.Zone, *source.SSHKey); err == nil {
+			clone.SSHKey = source.SSHKey
+		}
+	}
+
+	var snapshot *egoscale.Snapshot
+	decorateAsyncOperation(fmt.Sprintf("Creating snapshot of instance %q...", c.Instance), func() {
+		snapshot, err = cs.CreateInstanceSnapshot(ctx, c.Zone, source)
+	})
+	if err != nil {
+		return fmt.Errorf("error creating snapshot: %w", err)
+	}
+
+	var template *egoscale.Template
+	decorateAsyncOperation(fmt.Sprintf("Promoting snapshot %q to template...", *snapshot.ID), func() {
+		template, err = promoteSnapshotToTemplate(ctx, c.Zone, *snapshot.ID, oapi.PromoteSnapshotToTemplateJSONRequestBody{
+			Name:            fmt.Sprintf("%s-clone-%d", *source.Name, time.Now().Unix()),
+			Description:     utils.NonEmptyStringPtr(fmt.Sprintf("Clone of Compute instance %s", *source.Name)),
+			DefaultUser:     sourceTemplate.DefaultUser,
+			PasswordEnabled: sourceTemplate.PasswordEnabled,
+			SshKeyEnabled:   sourceTemplate.SSHKeyEnabled,
+		})
+	})
+	if err != nil {
+		return c.abort(ctx, fmt.Errorf("error promoting snapshot to template: %w", err), snapshot, nil, nil)
+	}
+	clone.TemplateID = template.ID
+
+	var created *egoscale.Instance
+	decorateAsyncOperation(fmt.Sprintf("Creating instance %q...", c.Name), func() {
+		created, err = cs.CreateInstance(ctx, c.Zone, clone)
+		if err != nil {
+			err = fmt.Errorf("error creating instance: %w", err)
+			return
+		}
+
+		if source.PrivateNetworkIDs != nil {
+			for _, id := range *source.PrivateNetworkIDs {
+				if err = cs.AttachInstanceToPrivateNetwork(
+					ctx,
+					c.Zone,
+					created,
+					&egoscale.PrivateNetwork{ID: &id},
+				); err != nil {
+					err = fmt.Errorf("error attaching instance to Private Network %s: %w", id, err)
+					return
+				}
+			}
+		}
+	})
+	if err != nil {
+		return c.abort(ctx, err, snapshot, template, created)
+	}
+	clone = created
+
+	// If the source instance was created with a single-use SSH key, the clone
+	// disk contains the same authorized key: copy the private key locally.
+	if sshKey, err := os.ReadFile(getInstanceSSHKeyPath(*source.ID)); err == nil {
+		privateKeyFilePath := getInstanceSSHKeyPath(*clone.ID)
+		if err = os.MkdirAll(path.Dir(privateKeyFilePath), 0o700); err != nil {
+			return fmt.Errorf("error writing SSH private key file: %w", err)
+		}
+		if err = os.WriteFile(privateKeyFilePath, sshKey, 0o600); err != nil {
+			return fmt.Errorf("error writing SSH private key file: %w", err)
+		}
+	}
+
+	if c.Cleanup {
+		decorateAsyncOperation("Deleting intermediate template and snapshot...", func() {
+			if err = cs.DeleteTemplate(ctx, c.Zone, template); err != nil {
+				return
+			}
+			err = cs.DeleteSnapshot(ctx, c.Zone, snapshot)
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting intermediate resources: %w", err)
+		}
+	}
+
+	if !gQuiet {
+		return (&instanceShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Instance:           *clone.ID,
+			Zone:               c.Zone,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+// abort handles a failure occurring once intermediate resources have been
+// created: they are deleted if the --cleanup flag is set, otherwise their IDs
+// are reported in the returned error so they can be deleted manually.
+func (c *instanceCloneCmd) abort(
+	ctx context.Context,
+	err error,
+	snapshot *egoscale.Snapshot,
+	template *egoscale.Template,
+	clone *egoscale.Instance,
+) error {
+	resources := make([]string, 0)
+	if clone != nil {
+		resources = append(resources, "instance "+*clone.ID)
+	}
+	if template != nil {
+		resources = append(resources, "template "+*template.ID)
+	}
+	resources = append(resources, "snapshot "+*snapshot.ID)
+
+	if !c.Cleanup {
+		return fmt.Errorf("%w (intermediate resources left: %s)", err, strings.Join(resources, ", "))
+	}
+
+	var cleanupErr error
+	decorateAsyncOperation("Deleting intermediate resources...", func() {
+		if clone != nil {
+			if cleanupErr = cs.DeleteInstance(ctx, c.Zone, clone); cleanupErr != nil {
+				return
+			}
+		}
+		if template != nil {
+			if cleanupErr = cs.DeleteTemplate(ctx, c.Zone, template); cleanupErr != nil {
+				return
+			}
+		}
+		cleanupErr = cs.DeleteSnapshot(ctx, c.Zone, snapshot)
+	})
+	if cleanupErr != nil {
+		return fmt.Errorf("%w (unable to delete intermediate resources %s: %v)",
+			err, strings.Join(resources, ", "), cleanupErr)
+	}
+
+	return err
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceCloneCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,601 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/pkg/sftp"
+	"github.com/spf13/cobra"
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
+)
+
+const transferBufferSize = 1024 * 1024
+
+// transferFile represents a file opened for reading or writing by a
+// transferFS.
+type transferFile interface {
+	io.ReaderAt
+	io.WriterAt
+	io.Closer
+}
+
+// transferFS represents a filesystem files can be copied from/to, either
+// local or remote.
+type transferFS interface {
+	stat(p string) (fs.FileInfo, error)
+	readDir(p string) ([]fs.FileInfo, error)
+	mkdir(p string, perm fs.FileMode) error
+	open(p string) (transferFile, error)
+	create(p string, perm fs.FileMode, truncate bool) (transferFile, error)
+	chtimes(p string, atime, mtime time.Time) error
+	join(elem ...string) string
+	base(p string) string
+	glob(pattern string) ([]string, error)
+}
+
+type localTransferFS struct{}
+
+func (localTransferFS) stat(p string) (fs.FileInfo, error) { return os.Stat(p) }
+
+func (localTransferFS) readDir(p string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, fi)
+	}
+
+	return infos, nil
+}
+
+func (localTransferFS) mkdir(p string, perm fs.FileMode) error { return os.Mkdir(p, perm) }
+
+func (localTransferFS) open(p string) (transferFile, error) { return os.Open(p) }
+
+func (localTransferFS) create(p string, perm fs.FileMode, truncate bool) (transferFile, error) {
+	flag := os.O_WRONLY | os.O_CREATE
+	if truncate {
+		flag |= os.O_TRUNC
+	}
+	return os.OpenFile(p, flag, perm)
+}
+
+func (localTransferFS) chtimes(p string, atime, mtime time.Time) error {
+	return os.Chtimes(p, atime, mtime)
+}
+
+func (localTransferFS) join(elem ...string) string { return filepath.Join(elem...) }
+
+func (localTransferFS) base(p string) string { return filepath.Base(p) }
+
+func (localTransferFS) glob(pattern string) ([]string, error) {
+	if !hasGlobMeta(pattern) {
+		return []string{pattern}, nil
+	}
+	return filepath.Glob(pattern)
+}
+
+type remoteTransferFS struct {
+	c *sftp.Client
+}
+
+func (r *remoteTransferFS) stat(p string) (fs.FileInfo, error) { return r.c.Stat(p) }
+
+func (r *remoteTransferFS) readDir(p string) ([]fs.FileInfo, error) { return r.c.ReadDir(p) }
+
+func (r *remoteTransferFS) mkdir(p string, perm fs.FileMode) error {
+	if err := r.c.Mkdir(p); err != nil {
+		return err
+	}
+	return r.c.Chmod(p, perm)
+}
+
+func (r *remoteTransferFS) open(p string) (transferFile, error) { return r.c.Open(p) }
+
+func (r *remoteTransferFS) create(p string, perm fs.FileMode, truncate bool) (transferFile, error) {
+	flag := os.O_WRONLY | os.O_CREATE
+	if truncate {
+		flag |= os.O_TRUNC
+	}
+
+	f, err := r.c.OpenFile(p, flag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.c.Chmod(p, perm); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (r *remoteTransferFS) chtimes(p string, atime, mtime time.Time) error {
+	return r.c.Chtimes(p, atime, mtime)
+}
+
+func (r *remoteTransferFS) join(elem ...string) string { return path.Join(elem...) }
+
+func (r *remoteTransferFS) base(p string) string { return path.Base(p) }
+
+func (r *remoteTransferFS) glob(pattern string) ([]string, error) {
+	if !hasGlobMeta(pattern) {
+		return []string{pattern}, nil
+	}
+	return r.c.Glob(pattern)
+}
+
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, `*?[`)
+}
+
+// transferPath represents a file transfer source or target, in the
+// [[INSTANCE]:]PATH format.
+type transferPath struct {
+	remote   bool
+	instance string
+	path     string
+}
+
+func parseTransferPath(s string) transferPath {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return transferPath{path: s}
+	}
+
+	prefix := s[:i]
+	if strings.ContainsAny(prefix, `/\`) || (runtime.GOOS == "windows" && len(prefix) == 1) {
+		return transferPath{path: s}
+	}
+
+	p := s[i+1:]
+	if p == "" {
+		p = "."
+	}
+
+	return transferPath{remote: true, instance: prefix, path: p}
+}
+
+type instanceCpCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"cp"`
+
+	Paths []string `cli-arg:"#" cli-usage:"[INSTANCE:]PATH"`
+
+	IPv6      bool   `cli-flag:"ipv6" cli-short:"6" cli-usage:"connect to the instances via their IPv6 address"`
+	Login     string `cli-usage:"SSH username to use for logging in (default: instance template default username)"`
+	Recursive bool   `cli-short:"r" cli-usage:"recursively copy entire directories"`
+	Resume    bool   `cli-usage:"resume interrupted transfers, skipping files already fully transferred (based on files size and modification time)"`
+	Selector  string `cli-short:"l" cli-usage:"label selector matching the instances to copy files to/from (requires an empty INSTANCE in the :PATH form)"`
+	Zone      string `cli-short:"z" cli-usage:"instances zone (default: all zones)"`
+}
+
+func (c *instanceCpCmd) cmdAliases() []string { return nil }
+
+func (c *instanceCpCmd) cmdShort() string {
+	return "Copy files to/from Compute instances via SFTP"
+}
+
+func (c *instanceCpCmd) cmdLong() string {
+	return `This command copies files (the last PATH being the TARGET, other PATHs the
+SOURCEs) between the local host and Compute instances, or between Compute
+instances, using the SFTP protocol (without requiring the scp(1) or sftp(1)
+commands). Remote paths are specified in the INSTANCE-NAME|ID:PATH form,
+relative paths being relative to the remote user home directory. Glob patterns
+are supported in SOURCE paths.
+
+When a label selector is specified (see "exo compute instance list --help" for
+the selector syntax), the INSTANCE part of remote paths must be left empty: files
+are then copied to/from all matching instances concurrently (bounded by the
+"--parallelism" global flag). Files copied from multiple instances are stored in
+per-instance subdirectories of TARGET.
+
+Examples:
+
+    exo compute instance cp ./dir web-1:/srv/ -r
+    exo compute instance cp web-1:'/var/log/*.log' ./logs/
+    exo compute instance cp web-1:/etc/app.conf web-2:/etc/app.conf
+    exo compute instance cp -l role=web ./app.tar.gz :/tmp/
+
+The SSH connection is authenticated the same way as "exo compute instance exec"
+(see "exo compute instance exec --help").
+`
+}
+
+func (c *instanceCpCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if len(c.Paths) < 2 {
+		return fmt.Errorf("missing arguments, run with --help for usage")
+	}
+
+	return nil
+}
+
+func (c *instanceCpCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	sources := make([]transferPath, len(c.Paths)-1)
+	for i, p := range c.Paths[:len(c.Paths)-1] {
+		sources[i] = parseTransferPath(p)
+		if sources[i].remote != sources[0].remote || sources[i].instance != sources[0].instance {
+			return fmt.Errorf("all SOURCE paths must be located on the same host")
+		}
+	}
+	target := parseTransferPath(c.Paths[len(c.Paths)-1])
+
+	if !sources[0].remote && !target.remote {
+		return fmt.Errorf("at least one of SOURCE or TARGET must be a remote path")
+	}
+
+	// The label selector applies to the remote paths with an empty INSTANCE.
+	if (sources[0].remote && sources[0].instance == "") || (target.remote && target.instance == "") {
+		if c.Selector == "" {
+			return fmt.Errorf("a label selector must be specified for remote paths without INSTANCE")
+		}
+	} else if c.Selector != "" {
+		return fmt.Errorf("the INSTANCE part of remote paths must be empty when using a label selector")
+	}
+
+	if sources[0].remote && target.remote && sources[0].instance == "" {
+		return fmt.Errorf("the source instance must be specified when copying files between instances")
+	}
+
+	zones := []string{c.Zone}
+	if c.Zone == "" {
+		zones = listZoneNames(cs.Client, gCurrentAccount)
+	}
+
+	var (
+		cache     = newResourceCache()
+		closers   = make([]func() error, 0)
+		closersMu sync.Mutex
+	)
+	defer func() {
+		for _, f := range closers {
+			_ = f()
+		}
+	}()
+
+	connect := func(instance *exov2.Instance) (transferFS, error) {
+		sshTarget, err := newInstanceSSHTarget(gContext, instance, c.Login, c.IPv6, "", cache)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := sshTarget.dial()
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect: %w", err)
+		}
+
+		client, err := sftp.NewClient(conn)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		closersMu.Lock()
+		closers = append(closers, client.Close, conn.Close)
+		closersMu.Unlock()
+
+		return &remoteTransferFS{c: client}, nil
+	}
+
+	var (
+		srcFS      transferFS = localTransferFS{}
+		dstFS      transferFS = localTransferFS{}
+		perTarget  bool
+		findTarget = target.instance
+	)
+
+	switch {
+	case sources[0].remote && target.remote:
+		source, err := findInstances(zones, []string{sources[0].instance}, "")
+		if err != nil {
+			return err
+		}
+		if len(source) > 1 {
+			return fmt.Errorf("multiple instances named %q found, please specify the instance ID", sources[0].instance)
+		}
+		if srcFS, err = connect(source[0]); err != nil {
+			return fmt.Errorf("instance %s: %w", *source[0].Name, err)
+		}
+		perTarget = true
+
+	case sources[0].remote:
+		findTarget = sources[0].instance
+
+	default:
+		perTarget = true
+	}
+
+	var names []string
+	if findTarget != "" {
+		names = []string{findTarget}
+	}
+	instances, err := findInstances(zones, names, c.Selector)
+	if err != nil {
+		return err
+	}
+	if findTarget != "" && len(instances) > 1 {
+		return fmt.Errorf("multiple instances named %q found, please specify the instance ID", findTarget)
+	}
+
+	progress := mpb.NewWithContext(gContext,
+		mpb.WithOutput(os.Stderr),
+		mpb.WithWidth(64),
+		mpb.WithRefreshRate(180*time.Millisecond),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+	)
+
+	transfer := &fileTransfer{
+		recursive: c.Recursive,
+		resume:    c.Resume,
+		progress:  progress,
+	}
+
+	err = forEachParallel(len(instances), func(i int) error {
+		instance := instances[i]
+
+		remoteFS, err := connect(instance)
+		if err != nil {
+			return fmt.Errorf("instance %s: %w", *instance.Name, err)
+		}
+
+		src, dst, dstPath := srcFS, dstFS, target.path
+		if perTarget {
+			dst = remoteFS
+		} else {
+			src = remoteFS
+
+			// When downloading files from multiple instances, store them in
+			// per-instance subdirectories.
+			if len(instances) > 1 {
+				dstPath = dst.join(target.path, *instance.Name)
+				if err := dst.mkdir(dstPath, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+					return fmt.Errorf("instance %s: %w", *instance.Name, err)
+				}
+			}
+		}
+
+		paths := make([]string, len(sources))
+		for i := range sources {
+			paths[i] = sources[i].path
+		}
+
+		if err := transfer.copy(*instance.Name, src, paths, dst, dstPath); err != nil {
+			return fmt.Errorf("instance %s: %w", *instance.Name, err)
+		}
+
+		return nil
+	})
+	progress.Wait()
+
+	return err
+}
+
+// fileTransfer implements recursive files copy between transferFS.
+type fileTransfer struct {
+	recursive bool
+	resume    bool
+	progress  *mpb.Progress
+}
+
+// copy copies the source paths (expanding glob patterns) to the target path,
+// following cp(1) semantics: if the target is an existing directory, sources
+// are copied into it.
+func (t *fileTransfer) copy(label string, src transferFS, sources []string, dst transferFS, target string) error {
+	paths := make([]string, 0)
+	for _, s := range sources {
+		matches, err := src.glob(s)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%s: no such file or directory", s)
+		}
+		paths = append(paths, matches...)
+	}
+
+	targetIsDir := false
+	if fi, err := dst.stat(target); err == nil && fi.IsDir() {
+		targetIsDir = true
+	}
+	if len(paths) > 1 && !targetIsDir {
+		return fmt.Errorf("%s: target is not a directory", target)
+	}
+
+	for _, p := range paths {
+		dstPath := target
+		if targetIsDir {
+			dstPath = dst.join(target, src.base(p))
+		}
+
+		if err := t.copyPath(label, src, p, dst, dstPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *fileTransfer) copyPath(label string, src transferFS, srcPath string, dst transferFS, dstPath string) error {
+	fi, err := src.stat(srcPath)
+	if err != nil {
+		return err
+	}
+
+	if !fi.IsDir() {
+		return t.copyFile(label, src, srcPath, fi, dst, dstPath)
+	}
+
+	if !t.recursive {
+		return fmt.Errorf("%s: is a directory (not copied, see --recursive)", srcPath)
+	}
+
+	if err := dst.mkdir(dstPath, fi.Mode().Perm()); err != nil {
+		if dfi, serr := dst.stat(dstPath); serr != nil || !dfi.IsDir() {
+			return err
+		}
+	}
+
+	entries, err := src.readDir(srcPath)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := t.copyPath(label, src, src.join(srcPath, e.Name()), dst, dst.join(dstPath, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *fileTransfer) copyFile(
+	label string,
+	src transferFS,
+	srcPath string,
+	fi fs.FileInfo,
+	dst transferFS,
+	dstPath string,
+) error {
+	var (
+		size   = fi.Size()
+		offset int64
+	)
+
+	if t.resume {
+		if dfi, err := dst.stat(dstPath); err == nil {
+			offset = transferResumeOffset(fi, dfi)
+		}
+	}
+
+	name := fmt.Sprintf("[%s] %s", label, srcPath)
+	bar := t.progress.AddBar(size,
+		mpb.PrependDecorators(
+			decor.Name(name, decor.WC{W: len(name) + 1, C: decor.DidentRight}),
+			decor.Percentage(decor.WCSyncSpace),
+		),
+		mpb.AppendDecorators(
+			decor.OnComplete(decor.AverageETA(decor.ET_STYLE_GO), "done"),
+		),
+	)
+	bar.SetCurrent(offset)
+
+	if offset == size && offset > 0 {
+		return nil
+	}
+
+	in, err := src.open(srcPath)
+	if err != nil {
+		bar.Abort(false)
+		return err
+	}
+	defer in.Close()
+
+	out, err := dst.create(dstPath, fi.Mode().Perm(), offset == 0)
+	if err != nil {
+		bar.Abort(false)
+		return err
+	}
+
+	if size == 0 {
+		bar.SetTotal(0, true)
+		if err := out.Close(); err != nil {
+			return err
+		}
+		return dst.chtimes(dstPath, time.Now(), fi.ModTime())
+	}
+
+	buf := make([]byte, transferBufferSize)
+	for offset < size {
+		n := int64(len(buf))
+		if size-offset < n {
+			n = size - offset
+		}
+
+		rn, rerr := in.ReadAt(buf[:n], offset)
+		if rn > 0 {
+			if _, err := out.WriteAt(buf[:rn], offset); err != nil {
+				_ = out.Close()
+				bar.Abort(false)
+				return err
+			}
+			offset += int64(rn)
+			bar.IncrBy(rn)
+		}
+
+		if rerr != nil {
+			if errors.Is(rerr, io.EOF) {
+				break
+			}
+			_ = out.Close()
+			bar.Abort(false)
+			return rerr
+		}
+	}
+
+	// The source file might have shrunk during the transfer.
+	if offset < size {
+		bar.SetTotal(offset, true)
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// The source modification time is preserved to mark the file as fully
+	// transferred, see transferResumeOffset().
+	return dst.chtimes(dstPath, time.Now(), fi.ModTime())
+}
+
+// transferResumeOffset returns the offset to resume the transfer of the
+// source file src at, given the existing target file dst. A target having the
+// same size and modification time as the source has been fully transferred.
+// Otherwise a target smaller than the source is considered as a partial
+// transfer of the current source version if it has been modified after the
+// source; in any other case the transfer starts over.
+func transferResumeOffset(src, dst fs.FileInfo) int64 {
+	if dst.IsDir() {
+		return 0
+	}
+
+	// SFTP only supports a 1 second resolution for modification times.
+	srcTime, dstTime := src.ModTime().Unix(), dst.ModTime().Unix()
+
+	switch {
+	case dst.Size() == src.Size() && dstTime == srcTime:
+		return dst.Size()
+	case dst.Size() < src.Size() && dstTime >= srcTime:
+		return dst.Size()
+	default:
+		return 0
+	}
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceCpCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+func (fi testFileInfo) Name() string       { return "test" }
+func (fi testFileInfo) Size() int64        { return fi.size }
+func (fi testFileInfo) Mode() fs.FileMode  { return 0o644 }
+func (fi testFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi testFileInfo) IsDir() bool        { return false }
+func (fi testFileInfo) Sys() interface{}   { return nil }
+
+func Test_transferResumeOffset(t *testing.T) {
+	now := time.Now()
+	src := testFileInfo{size: 100, modTime: now.Add(-time.Hour).Truncate(time.Second)}
+
+	tests := []struct {
+		name     string
+		dst      testFileInfo
+		expected int64
+	}{
+		{
+			name:     "fully transferred",
+			dst:      testFileInfo{size: 100, modTime: src.modTime.Add(time.Millisecond)},
+			expected: 100,
+		},
+		{
+			name:     "same size but different modification time",
+			dst:      testFileInfo{size: 100, modTime: now},
+			expected: 0,
+		},
+		{
+			name:     "partial transfer",
+			dst:      testFileInfo{size: 40, modTime: now},
+			expected: 40,
+		},
+		{
+			name:     "partial transfer of a previous source version",
+			dst:      testFileInfo{size: 40, modTime: src.modTime.Add(-time.Hour)},
+			expected: 0,
+		},
+		{
+			name:     "larger target",
+			dst:      testFileInfo{size: 200, modTime: now},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, transferResumeOffset(src, tt.dst))
+		})
+	}
+}
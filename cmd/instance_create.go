@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -8,7 +9,10 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/exoscale/cli/utils"
@@ -26,8 +30,10 @@ type instanceCreateCmd struct {
 	Name string `cli-arg:"#" cli-usage:"NAME"`
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-usage:"instance Anti-Affinity Group NAME|ID (can be specified multiple times)"`
-	CloudInitFile      string            `cli-flag:"cloud-init" cli-usage:"instance cloud-init user data configuration file path"`
+	CloudInitFile      []string          `cli-flag:"cloud-init" cli-usage:"instance cloud-init user data configuration file path (can be specified multiple times)"`
 	CloudInitCompress  bool              `cli-flag:"cloud-init-compress" cli-usage:"compress instance cloud-init user data"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"instance cloud-init user data template variable (format: key=value)"`
+	Count              int64             `cli-usage:"number of instances to create"`
 	DeployTarget       string            `cli-usage:"instance Deploy Target NAME|ID"`
 	DiskSize           int64             `cli-usage:"instance disk size"`
 	IPv6               bool              `cli-flag:"ipv6" cli-usage:"enable IPv6 on instance"`
@@ -35,8 +41,10 @@ type instanceCreateCmd struct {
 	Labels             map[string]string `cli-flag:"label" cli-usage:"instance label (format: key=value)"`
 	PrivateNetworks    []string          `cli-flag:"private-network" cli-usage:"instance Private Network NAME|ID (can be specified multiple times)"`
 	PrivateInstance    bool              `cli-flag:"private-instance" cli-usage:"enable private instance to be created"`
+	Protect            bool              `cli-usage:"enable instance deletion protection"`
 	SSHKey             string            `cli-flag:"ssh-key" cli-usage:"SSH key to deploy on the instance"`
 	SecurityGroups     []string          `cli-flag:"security-group" cli-usage:"instance Security Group NAME|ID (can be specified multiple times)"`
+	SpreadGroup        string            `cli-flag:"spread-anti-affinity-group" cli-usage:"create an Anti-Affinity Group NAME to spread the instances across (requires --count)"`
 	Template           string            `cli-usage:"instance template NAME|ID"`
 	TemplateVisibility string            `cli-usage:"instance template visibility (public|private)"`
 	Zone               string            `cli-short:"z" cli-usage:"instance zone"`
@@ -49,11 +57,22 @@ func (c *instanceCreateCmd) cmdShort() string { return "Create a Compute instanc
 func (c *instanceCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates a Compute instance.
 
+Using the --count flag, multiple instances are created concurrently. In this
+mode NAME is a template receiving the instance index (starting at 1) as
+{{.Index}}; if NAME doesn't reference the index, "-{{.Index}}" is appended
+to it. Example:
+
+    exo compute instance create "web-{{.Index}}" --count 3 \
+        --spread-anti-affinity-group web
+
+%s
+
 Supported Compute instance type families: %s
 
 Supported Compute instance type sizes: %s
 
 Supported output template annotations: %s`,
+		cloudInitHelp,
 		strings.Join(instanceTypeFamilies, ", "),
 		strings.Join(instanceTypeSizes, ", "),
 		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "))
@@ -62,7 +81,48 @@ Supported output template annotations: %s`,
 func (c *instanceCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
 	cmdSetTemplateFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Count < 1 {
+		cmdExitOnUsageError(cmd, "--count must be greater than or equal to 1")
+	}
+
+	if c.SpreadGroup != "" && c.Count < 2 {
+		return fmt.Errorf("--spread-anti-affinity-group requires --count to be greater than 1")
+	}
+
+	return nil
+}
+
+// instanceNames returns the names of the instances to create, rendered from
+// the NAME template in case multiple instances are requested or if NAME
+// references the instance index.
+func (c *instanceCreateCmd) instanceNames() ([]string, error) {
+	name := c.Name
+	if !strings.Contains(name, "{{") {
+		if c.Count <= 1 {
+			return []string{name}, nil
+		}
+		name += "-{{.Index}}"
+	}
+
+	tpl, err := template.New("name").Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance name template: %w", err)
+	}
+
+	names := make([]string, c.Count)
+	for i := range names {
+		var buf strings.Builder
+		if err := tpl.Execute(&buf, struct{ Index int }{Index: i + 1}); err != nil {
+			return nil, fmt.Errorf("invalid instance name template: %w", err)
+		}
+		names[i] = buf.String()
+	}
+
+	return names, nil
 }
 
 func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -72,6 +132,22 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		sshKey                 *egoscale.SSHKey
 	)
 
+	names, err := c.instanceNames()
+	if err != nil {
+		return err
+	}
+
+	// Rendering the cloud-init user data of every instance upfront, so that
+	// errors are reported before any resource gets created.
+	userData := make([]*string, len(names))
+	if len(c.CloudInitFile) > 0 {
+		for i := range names {
+			if userData[i], err = c.userData(names[i]); err != nil {
+				return err
+			}
+		}
+	}
+
 	instance := &egoscale.Instance{
 		DiskSize:    &c.DiskSize,
 		IPv6Enabled: &c.IPv6,
@@ -81,7 +157,7 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 			}
 			return
 		}(),
-		Name:   &c.Name,
+		Name:   &names[0],
 		SSHKey: utils.NonEmptyStringPtr(c.SSHKey),
 	}
 
@@ -163,7 +239,7 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		sshKey, err = cs.RegisterSSHKey(
 			ctx,
 			c.Zone,
-			fmt.Sprintf("%s-%d", c.Name, time.Now().Unix()),
+			fmt.Sprintf("%s-%d", names[0], time.Now().Unix()),
 			string(ssh.MarshalAuthorizedKey(singleUseSSHPublicKey)),
 		)
 		if err != nil {
@@ -184,14 +260,12 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 	instance.TemplateID = template.ID
 
-	if c.CloudInitFile != "" {
-		userData, err := getUserDataFromFile(c.CloudInitFile, c.CloudInitCompress)
-		if err != nil {
-			return fmt.Errorf("error parsing cloud-init user data: %w", err)
-		}
-		instance.UserData = &userData
+	if len(names) > 1 {
+		return c.createInstances(ctx, names, userData, instance, instanceType, privateNetworks, singleUseSSHPrivateKey, sshKey)
 	}
 
+	instance.UserData = userData[0]
+
 	decorateAsyncOperation(fmt.Sprintf("Creating instance %q...", c.Name), func() {
 		instance, err = cs.CreateInstance(ctx, c.Zone, instance)
 		if err != nil {
@@ -203,27 +277,18 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 				return
 			}
 		}
+
+		if c.Protect {
+			err = setInstanceDeletionProtection(ctx, *instance.ID, true)
+		}
 	})
 	if err != nil {
 		return err
 	}
 
 	if singleUseSSHPrivateKey != nil {
-		privateKeyFilePath := getInstanceSSHKeyPath(*instance.ID)
-
-		if err = os.MkdirAll(path.Dir(privateKeyFilePath), 0o700); err != nil {
-			return fmt.Errorf("error writing SSH private key file: %w", err)
-		}
-
-		if err = os.WriteFile(
-			privateKeyFilePath,
-			pem.EncodeToMemory(&pem.Block{
-				Type:  "RSA PRIVATE KEY",
-				Bytes: x509.MarshalPKCS1PrivateKey(singleUseSSHPrivateKey),
-			}),
-			0o600,
-		); err != nil {
-			return fmt.Errorf("error writing SSH private key file: %w", err)
+		if err = writeInstanceSSHPrivateKey(*instance.ID, singleUseSSHPrivateKey); err != nil {
+			return err
 		}
 
 		if err = cs.DeleteSSHKey(ctx, c.Zone, sshKey); err != nil {
@@ -242,10 +307,143 @@ func (c *instanceCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// userData returns the cloud-init user data of the instance to create.
+func (c *instanceCreateCmd) userData(name string) (*string, error) {
+	userData, err := buildUserData(c.CloudInitFile, &cloudInitTemplateData{
+		Name: name,
+		Zone: c.Zone,
+		Vars: c.CloudInitVars,
+	}, c.CloudInitCompress)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing cloud-init user data: %w", err)
+	}
+
+	return &userData, nil
+}
+
+// createInstances creates concurrently one instance per name specified, based
+// on the instance spec whose dependencies have already been resolved and on
+// the per-instance cloud-init user data.
+func (c *instanceCreateCmd) createInstances(
+	ctx context.Context,
+	names []string,
+	userData []*string,
+	spec *egoscale.Instance,
+	instanceType *egoscale.InstanceType,
+	privateNetworks []*egoscale.PrivateNetwork,
+	singleUseSSHPrivateKey *rsa.PrivateKey,
+	sshKey *egoscale.SSHKey,
+) error {
+	if c.SpreadGroup != "" {
+		antiAffinityGroup, err := cs.CreateAntiAffinityGroup(ctx, c.Zone, &egoscale.AntiAffinityGroup{
+			Name: &c.SpreadGroup,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating Anti-Affinity Group: %w", err)
+		}
+
+		antiAffinityGroupIDs := []string{*antiAffinityGroup.ID}
+		if spec.AntiAffinityGroupIDs != nil {
+			antiAffinityGroupIDs = append(antiAffinityGroupIDs, *spec.AntiAffinityGroupIDs...)
+		}
+		spec.AntiAffinityGroupIDs = &antiAffinityGroupIDs
+	}
+
+	instances := make([]*egoscale.Instance, len(names))
+	for i := range names {
+		instance := *spec
+		instance.Name = &names[i]
+		instance.UserData = userData[i]
+		instances[i] = &instance
+	}
+
+	var (
+		out = make(instanceListOutput, 0)
+		mu  sync.Mutex
+	)
+
+	err := bulkInstanceOperation("Creating instance", instances, func(instance *egoscale.Instance) error {
+		created, err := cs.CreateInstance(ctx, c.Zone, instance)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range privateNetworks {
+			if err = cs.AttachInstanceToPrivateNetwork(ctx, c.Zone, created, p); err != nil {
+				return err
+			}
+		}
+
+		if c.Protect {
+			if err = setInstanceDeletionProtection(ctx, *created.ID, true); err != nil {
+				return err
+			}
+		}
+
+		if singleUseSSHPrivateKey != nil {
+			if err = writeInstanceSSHPrivateKey(*created.ID, singleUseSSHPrivateKey); err != nil {
+				return err
+			}
+		}
+
+		mu.Lock()
+		out = append(out, instanceListItemOutput{
+			ID:        *created.ID,
+			Name:      *created.Name,
+			Zone:      c.Zone,
+			Type:      fmt.Sprintf("%s.%s", *instanceType.Family, *instanceType.Size),
+			IPAddress: utils.DefaultIP(created.PublicIPAddress, emptyIPAddressVisualization),
+			State:     *created.State,
+		})
+		mu.Unlock()
+
+		return nil
+	})
+
+	if sshKey != nil {
+		if err := cs.DeleteSSHKey(ctx, c.Zone, sshKey); err != nil {
+			return fmt.Errorf("error deleting SSH key: %w", err)
+		}
+	}
+
+	if !gQuiet && len(out) > 0 {
+		sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+		if err := c.outputFunc(&out, nil); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// writeInstanceSSHPrivateKey writes the single-use SSH private key generated
+// during the creation of a Compute instance to the instance local directory.
+func writeInstanceSSHPrivateKey(id string, key *rsa.PrivateKey) error {
+	privateKeyFilePath := getInstanceSSHKeyPath(id)
+
+	if err := os.MkdirAll(path.Dir(privateKeyFilePath), 0o700); err != nil {
+		return fmt.Errorf("error writing SSH private key file: %w", err)
+	}
+
+	if err := os.WriteFile(
+		privateKeyFilePath,
+		pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}),
+		0o600,
+	); err != nil {
+		return fmt.Errorf("error writing SSH private key file: %w", err)
+	}
+
+	return nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceCreateCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
 
+		Count:              1,
 		DiskSize:           50,
 		InstanceType:       fmt.Sprintf("%s.%s", defaultInstanceTypeFamily, defaultInstanceType),
 		TemplateVisibility: defaultTemplateVisibility,
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -15,26 +16,71 @@ type instanceDeleteCmd struct {
 
 	_ bool `cli-cmd:"delete"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Async    bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
+	Force    bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Selector string `cli-short:"l" cli-usage:"label selector of the instances to delete (e.g. env=prod,role!=db)"`
+	Zone     string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceDeleteCmd) cmdAliases() []string { return gRemoveAlias }
 
 func (c *instanceDeleteCmd) cmdShort() string { return "Delete a Compute instance" }
 
-func (c *instanceDeleteCmd) cmdLong() string { return "" }
+func (c *instanceDeleteCmd) cmdLong() string {
+	return fmt.Sprintf(`This command deletes a Compute instance, or all the Compute instances of
+the zone matching the label selector specified using the --selector flag.
+
+%s
+
+Example:
+
+    exo compute instance delete --selector env=staging,role!=db
+`,
+		labelSelectorHelp)
+}
 
 func (c *instanceDeleteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+	if err := validateInstanceOrSelector(c.Instance, c.Selector); err != nil {
+		return err
+	}
+	if c.Async && c.Selector != "" {
+		return fmt.Errorf("--async cannot be used with --selector")
+	}
+	return nil
 }
 
 func (c *instanceDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	if c.Selector != "" {
+		instances, err := findInstancesBySelector(ctx, c.Zone, c.Selector)
+		if err != nil {
+			return err
+		}
+
+		if !c.Force {
+			if !askBulkInstanceQuestion("delete", instances) {
+				return nil
+			}
+		}
+
+		return bulkInstanceOperation("Deleting instance", instances, func(instance *egoscale.Instance) error {
+			if err := checkInstanceDeletionProtection(ctx, instance); err != nil {
+				return err
+			}
+			if err := cs.DeleteInstance(ctx, c.Zone, instance); err != nil {
+				return err
+			}
+			return removeInstanceDir(*instance.ID)
+		})
+	}
+
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
@@ -43,20 +89,46 @@ func (c *instanceDeleteCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if err := checkInstanceDeletionProtection(ctx, instance); err != nil {
+		return err
+	}
+
 	if !c.Force {
 		if !askQuestion(fmt.Sprintf("Are you sure you want to delete instance %q?", c.Instance)) {
 			return nil
 		}
 	}
 
-	decorateAsyncOperation(fmt.Sprintf("Deleting instance %q...", c.Instance), func() {
-		err = cs.DeleteInstance(ctx, c.Zone, instance)
-	})
-	if err != nil {
-		return err
+	if c.Async {
+		resp, err := cs.DeleteInstanceWithResponse(ctx, *instance.ID)
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		if err := c.outputFunc(newOperationShowOutput(op, c.Zone), nil); err != nil {
+			return err
+		}
+	} else {
+		decorateAsyncOperation(fmt.Sprintf("Deleting instance %q...", c.Instance), func() {
+			err = cs.DeleteInstance(ctx, c.Zone, instance)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	instanceDir := path.Join(gConfigFolder, "instances", *instance.ID)
+	return removeInstanceDir(*instance.ID)
+}
+
+// removeInstanceDir removes the local directory storing the data of a
+// Compute instance (e.g. its SSH private key), if any.
+func removeInstanceDir(id string) error {
+	instanceDir := path.Join(gConfigFolder, "instances", id)
 	if _, err := os.Stat(instanceDir); !os.IsNotExist(err) {
 		if err := os.RemoveAll(instanceDir); err != nil {
 			return fmt.Errorf("error deleting instance directory: %w", err)
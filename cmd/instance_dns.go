@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"net"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/spf13/cobra"
+)
+
+var instanceDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage Compute instances forward and reverse DNS records",
+	Long: `These commands manage the forward DNS records (A/AAAA) of Compute instances together
+with their reverse DNS, so that they pass Forward-Confirmed reverse DNS
+(FCrDNS) checks: "register" creates the records in Exoscale DNS and sets the
+reverse DNS to the same FQDN, "unregister" removes both, and "register
+--check" reports the mismatches across all the Compute instances of the account.`,
+}
+
+func init() {
+	instanceCmd.AddCommand(instanceDNSCmd)
+}
+
+// newInstanceFCrDNSTarget returns a fcrdnsTarget for the specified Compute
+// instance.
+func newInstanceFCrDNSTarget(instance *exov2.Instance, zone string) *fcrdnsTarget {
+	addresses := make([]net.IP, 0)
+	if instance.PublicIPAddress != nil {
+		addresses = append(addresses, *instance.PublicIPAddress)
+	}
+	if instance.IPv6Address != nil {
+		addresses = append(addresses, *instance.IPv6Address)
+	}
+
+	return &fcrdnsTarget{
+		id:        *instance.ID,
+		name:      *instance.Name,
+		zone:      zone,
+		addresses: addresses,
+
+		getReverseDNS: func(ctx context.Context) (string, error) {
+			return cs.GetInstanceReverseDNS(ctx, zone, *instance.ID)
+		},
+		updateReverseDNS: func(ctx context.Context, fqdn string) error {
+			return cs.UpdateInstanceReverseDNS(ctx, zone, *instance.ID, fqdn)
+		},
+		deleteReverseDNS: func(ctx context.Context) error {
+			return cs.DeleteInstanceReverseDNS(ctx, zone, *instance.ID)
+		},
+	}
+}
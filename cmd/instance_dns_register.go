@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceDNSRegisterCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"register"`
+
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
+
+	Check  bool   `cli-usage:"report the forward and reverse DNS mismatches of all the Compute instances instead"`
+	Domain string `cli-usage:"Exoscale DNS domain to register the instance in"`
+	Name   string `cli-usage:"DNS record name (default: instance name)"`
+	TTL    int64  `cli-flag:"ttl" cli-usage:"DNS records TTL (in seconds)"`
+	Zone   string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceDNSRegisterCmd) cmdAliases() []string { return nil }
+
+func (c *instanceDNSRegisterCmd) cmdShort() string {
+	return "Register a Compute instance in DNS"
+}
+
+func (c *instanceDNSRegisterCmd) cmdLong() string {
+	return fmt.Sprintf(`This command creates (or updates) the DNS A/AAAA records NAME.DOMAIN
+pointing to the Compute instance public IPv4/IPv6 addresses, and sets the
+instance reverse DNS to the same FQDN so that it passes Forward-Confirmed
+reverse DNS (FCrDNS) checks. DOMAIN must be managed in Exoscale DNS.
+
+Using the --check flag, no records are modified: the command checks instead
+for each Compute instance (in all zones, or in the zone specified using the
+--zone flag) that its reverse DNS resolves to its public IP addresses via the
+DNS records managed in Exoscale DNS, and reports mismatches.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&fcrdnsCheckItemOutput{}), ", "))
+}
+
+func (c *instanceDNSRegisterCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Check {
+		if c.Instance != "" {
+			cmdExitOnUsageError(cmd, "--check doesn't accept an argument")
+		}
+		return nil
+	}
+
+	if c.Instance == "" {
+		cmdExitOnUsageError(cmd, "missing arguments, run with --help for usage")
+	}
+
+	return cmdCheckRequiredFlags(cmd, []string{"domain"})
+}
+
+func (c *instanceDNSRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	if c.Check {
+		return c.check(cmd)
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	if c.Name == "" {
+		c.Name = *instance.Name
+	}
+
+	target := newInstanceFCrDNSTarget(instance, c.Zone)
+
+	decorateAsyncOperation(fmt.Sprintf("Registering instance %q in DNS...", c.Instance), func() {
+		err = fcrdnsRegister(ctx, target, c.Domain, c.Name, c.TTL)
+	})
+	if err != nil {
+		return err
+	}
+
+	domains, err := cs.ListDNSDomains(ctx, c.Zone)
+	if err != nil {
+		return fmt.Errorf("unable to list DNS domains: %w", err)
+	}
+
+	out, err := fcrdnsCheck(ctx, target, domains, newResourceCache())
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFunc(out, nil); err != nil {
+		return err
+	}
+
+	if out.Status != fcrdnsStatusOK {
+		return fmt.Errorf("DNS records inconsistency detected: %s", out.Status)
+	}
+
+	return nil
+}
+
+// check reports the forward and reverse DNS consistency of all the
+// resources, restricted to the zone specified using the --zone flag if any.
+func (c *instanceDNSRegisterCmd) check(cmd *cobra.Command) error {
+	zones := []string{c.Zone}
+	if !cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Zone)) {
+		zones = listZoneNames(cs.Client, gCurrentAccount)
+	}
+
+	out, err := fcrdnsCheckAll(zones, func(ctx context.Context, zone string) ([]*fcrdnsTarget, error) {
+		list, err := cs.ListInstances(ctx, zone)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list Compute instances in zone %s: %w", zone, err)
+		}
+
+		targets := make([]*fcrdnsTarget, len(list))
+		for i := range list {
+			targets[i] = newInstanceFCrDNSTarget(list[i], zone)
+		}
+		return targets, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.outputFunc(&out, nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceDNSCmd, &instanceDNSRegisterCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+
+		TTL: 3600,
+	}))
+}
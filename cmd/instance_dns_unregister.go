@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceDNSUnregisterCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"unregister"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceDNSUnregisterCmd) cmdAliases() []string { return nil }
+
+func (c *instanceDNSUnregisterCmd) cmdShort() string {
+	return "Unregister a Compute instance from DNS"
+}
+
+func (c *instanceDNSUnregisterCmd) cmdLong() string {
+	return `This command deletes the DNS A/AAAA records matching the Compute instance
+reverse DNS and pointing to its public IP addresses, then deletes the
+instance reverse DNS.`
+}
+
+func (c *instanceDNSUnregisterCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceDNSUnregisterCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	if !c.Force {
+		if !askQuestion(fmt.Sprintf("Are you sure you want to unregister instance %q from DNS?", c.Instance)) {
+			return nil
+		}
+	}
+
+	decorateAsyncOperation(fmt.Sprintf("Unregistering instance %q from DNS...", c.Instance), func() {
+		err = fcrdnsUnregister(ctx, newInstanceFCrDNSTarget(instance, c.Zone))
+	})
+
+	return err
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceDNSCmd, &instanceDNSUnregisterCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
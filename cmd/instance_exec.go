@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+type instanceExecItemOutput struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Zone     string `json:"zone"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+type instanceExecOutput []instanceExecItemOutput
+
+func (o *instanceExecOutput) toJSON()  { outputJSON(o) }
+func (o *instanceExecOutput) toText()  { outputText(o) }
+func (o *instanceExecOutput) toTable() { outputTable(o) }
+
+// prefixWriter is an io.Writer writing complete lines to the underlying
+// writer prefixed with a string. Writes to the underlying writer are
+// serialized using the specified mutex, so that lines written concurrently
+// by multiple prefixWriters sharing it don't get interleaved.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		line := w.buf.Next(i + 1)
+		w.mu.Lock()
+		_, err := fmt.Fprintf(w.w, "%s%s", w.prefix, line)
+		w.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any remaining incomplete line to the underlying writer.
+func (w *prefixWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.mu.Lock()
+		fmt.Fprintf(w.w, "%s%s\n", w.prefix, w.buf.String())
+		w.mu.Unlock()
+		w.buf.Reset()
+	}
+}
+
+type instanceExecCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	command []string `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"exec"`
+
+	Instances []string `cli-arg:"?" cli-usage:"INSTANCE-NAME|ID"`
+
+	IPv6     bool   `cli-flag:"ipv6" cli-short:"6" cli-usage:"connect to the instances via their IPv6 address"`
+	Login    string `cli-usage:"SSH username to use for logging in (default: instance template default username)"`
+	Selector string `cli-short:"l" cli-usage:"label selector matching the instances to run the command on"`
+	Zone     string `cli-short:"z" cli-usage:"instances zone (default: all zones)"`
+}
+
+func (c *instanceExecCmd) cmdAliases() []string { return nil }
+
+func (c *instanceExecCmd) cmdShort() string {
+	return "Run a command on Compute instances via SSH"
+}
+
+func (c *instanceExecCmd) cmdLong() string {
+	return fmt.Sprintf(`This command runs a command via SSH on one or several Compute instances
+concurrently (bounded by the "--parallelism" global flag). Target instances are
+specified either by name/ID or using a label selector (see "exo compute instance
+list --help" for the selector syntax); the command to run must be specified
+after a "--" separator:
+
+    exo compute instance exec -l role=web -- uptime
+    exo compute instance exec web1 web2 -- sudo systemctl restart nginx
+
+The command arguments are quoted before being passed to the remote shell,
+unless the command is specified as a single argument, in which case it is
+interpreted by the remote shell as-is (e.g. to use pipes or redirections):
+
+    exo compute instance exec web1 -- sh -c 'echo a b'
+    exo compute instance exec web1 -- 'ps aux | grep nginx'
+
+The SSH connection is authenticated using the keys available from the SSH
+agent, the single-use SSH key generated at instance creation (if any) and the
+user default SSH keys (~/.ssh/id_{ed25519,ecdsa,rsa}). Remote host keys are
+trusted on first use and recorded in the "known_hosts" file of the exo CLI
+configuration directory.
+
+The command output is printed prefixed with the instance name. If the "json"
+output format is requested, a summary of the command exit codes is printed
+instead.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceExecItemOutput{}), ", "))
+}
+
+func (c *instanceExecCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 || dash == len(args) {
+		return fmt.Errorf("missing command, run with --help for usage")
+	}
+	c.command = args[dash:]
+
+	if err := cliCommandDefaultPreRun(c, cmd, args[:dash]); err != nil {
+		return err
+	}
+
+	if len(c.Instances) == 0 && c.Selector == "" {
+		return fmt.Errorf("missing arguments, run with --help for usage")
+	}
+	if len(c.Instances) > 0 && c.Selector != "" {
+		return fmt.Errorf("instance NAMEs|IDs and a label selector cannot be specified at the same time")
+	}
+
+	return nil
+}
+
+func (c *instanceExecCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	zones := []string{c.Zone}
+	if c.Zone == "" {
+		zones = listZoneNames(cs.Client, gCurrentAccount)
+	}
+
+	instances, err := findInstances(zones, c.Instances, c.Selector)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(instances, func(i, j int) bool { return *instances[i].Name < *instances[j].Name })
+
+	var (
+		out      = make(instanceExecOutput, len(instances))
+		cache    = newResourceCache()
+		outputMu sync.Mutex
+	)
+
+	// Command failures are reported per instance in the output, errors are
+	// never returned to forEachParallel.
+	_ = forEachParallel(len(instances), func(i int) error {
+		instance := instances[i]
+
+		out[i] = instanceExecItemOutput{
+			ID:   *instance.ID,
+			Name: *instance.Name,
+			Zone: *instance.Zone,
+		}
+
+		var stdout, stderr io.Writer = io.Discard, io.Discard
+		if gOutputFormat != "json" {
+			prefix := fmt.Sprintf("[%s] ", *instance.Name)
+			o := &prefixWriter{w: os.Stdout, mu: &outputMu, prefix: prefix}
+			e := &prefixWriter{w: os.Stderr, mu: &outputMu, prefix: prefix}
+			defer o.Flush()
+			defer e.Flush()
+			stdout, stderr = o, e
+		}
+
+		exitCode, err := c.runCommand(instance, cache, stdout, stderr)
+		out[i].ExitCode = exitCode
+		if err != nil {
+			out[i].Error = err.Error()
+			if gOutputFormat != "json" {
+				outputMu.Lock()
+				fmt.Fprintf(os.Stderr, "[%s] error: %v\n", *instance.Name, err)
+				outputMu.Unlock()
+			}
+		}
+
+		return nil
+	})
+
+	if gOutputFormat == "json" {
+		c.outputFunc(&out, nil) // nolint:errcheck
+	}
+
+	failed := 0
+	for _, r := range out {
+		if r.ExitCode != 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("command failed on %d/%d instances", failed, len(out))
+	}
+
+	return nil
+}
+
+// runCommand runs the command on the specified instance, returning its exit
+// code (-1 if the command could not be run).
+func (c *instanceExecCmd) runCommand(
+	instance *exov2.Instance,
+	cache *resourceCache,
+	stdout, stderr io.Writer,
+) (int, error) {
+	target, err := newInstanceSSHTarget(gContext, instance, c.Login, c.IPv6, "", cache)
+	if err != nil {
+		return -1, err
+	}
+
+	client, err := target.dial()
+	if err != nil {
+		return -1, fmt.Errorf("unable to connect: %w", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("unable to open SSH session: %w", err)
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	command := c.command[0]
+	if len(c.command) > 1 {
+		command = shellquote.Join(c.command...)
+	}
+
+	if err := session.Run(command); err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceExecCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -35,7 +35,8 @@ type instanceListCmd struct {
 
 	_ bool `cli-cmd:"list"`
 
-	Zone string `cli-short:"z" cli-usage:"zone to filter results to"`
+	Selector string `cli-short:"l" cli-usage:"label selector to filter results with (e.g. env=prod,role!=db)"`
+	Zone     string `cli-short:"z" cli-usage:"zone to filter results to"`
 }
 
 func (c *instanceListCmd) cmdAliases() []string { return gListAlias }
@@ -45,6 +46,10 @@ func (c *instanceListCmd) cmdShort() string { return "List Compute instances" }
 func (c *instanceListCmd) cmdLong() string {
 	return fmt.Sprintf(`This command lists Compute instances.
 
+Results can be filtered on instances labels using a label selector, i.e. a
+comma-separated list of requirements: "KEY=VALUE", "KEY!=VALUE", "KEY" (label
+is set) and "!KEY" (label is not set).
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instanceListItemOutput{}), ", "))
 }
@@ -54,19 +59,33 @@ func (c *instanceListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
-	var zones []string
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *instanceListCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
+	var (
+		zones    []string
+		selector labelSelector
+		err      error
+	)
+
+	if c.Selector != "" {
+		if selector, err = parseLabelSelector(c.Selector); err != nil {
+			return nil, err
+		}
+	}
 
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(client, acc)
 	}
 
 	out := make(instanceListOutput, 0)
 	res := make(chan instanceListItemOutput)
 	done := make(chan struct{})
 
-	instanceTypes := make(map[string]*egoscale.InstanceType) // For caching
+	cache := newResourceCache()
 
 	go func() {
 		for instance := range res {
@@ -74,25 +93,25 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 		done <- struct{}{}
 	}()
-	err := forEachZone(zones, func(zone string) error {
-		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+	err = forEachZone(zones, func(zone string) error {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, zone))
 
-		list, err := cs.ListInstances(ctx, zone)
+		list, err := client.ListInstances(ctx, zone)
 		if err != nil {
 			return fmt.Errorf("unable to list Compute instances in zone %s: %w", zone, err)
 		}
 
 		for _, i := range list {
-			instanceType, cached := instanceTypes[*i.InstanceTypeID]
-			if !cached {
-				instanceType, err = cs.GetInstanceType(ctx, zone, *i.InstanceTypeID)
-				if err != nil {
-					return fmt.Errorf(
-						"unable to retrieve Compute instance type %q: %w",
-						*i.InstanceTypeID,
-						err)
-				}
-				instanceTypes[*i.InstanceTypeID] = instanceType
+			if selector != nil && !selector.matches(instanceLabels(i)) {
+				continue
+			}
+
+			instanceType, err := cache.instanceType(ctx, client, zone, *i.InstanceTypeID)
+			if err != nil {
+				return fmt.Errorf(
+					"unable to retrieve Compute instance type %q: %w",
+					*i.InstanceTypeID,
+					err)
 			}
 
 			res <- instanceListItemOutput{
@@ -115,7 +134,7 @@ func (c *instanceListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	close(res)
 	<-done
 
-	return c.outputFunc(&out, nil)
+	return &out, nil
 }
 
 func init() {
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -29,6 +31,48 @@ commands.
 	Hidden: true,
 }
 
+// instancePoolNLBHealth returns the health of the members of an Instance Pool
+// on the Network Load Balancer services targeting it, indexed by public IP
+// address: a member is healthy if it is healthy on every service. The
+// returned map is nil if no service targets the Instance Pool.
+func instancePoolNLBHealth(ctx context.Context, zone, instancePoolID string) (map[string]bool, error) {
+	nlbs, err := cs.ListNetworkLoadBalancers(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		services int
+		healthy  = make(map[string]int)
+	)
+	for _, nlb := range nlbs {
+		for _, svc := range nlb.Services {
+			if svc.InstancePoolID == nil || *svc.InstancePoolID != instancePoolID {
+				continue
+			}
+			services++
+
+			for _, st := range svc.HealthcheckStatus {
+				if st.InstanceIP != nil && st.Status != nil &&
+					*st.Status == string(oapi.LoadBalancerServerStatusStatusSuccess) {
+					healthy[st.InstanceIP.String()]++
+				}
+			}
+		}
+	}
+
+	if services == 0 {
+		return nil, nil
+	}
+
+	health := make(map[string]bool)
+	for address, n := range healthy {
+		health[address] = n == services
+	}
+
+	return health, nil
+}
+
 func init() {
 	computeCmd.AddCommand(instancePoolCmd)
 
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+const (
+	instancePoolAutoscaleActionNone      = "none"
+	instancePoolAutoscaleActionScaleUp   = "scale-up"
+	instancePoolAutoscaleActionScaleDown = "scale-down"
+)
+
+// instancePoolAutoscaleEvent represents an autoscaler evaluation, logged as a
+// JSON object.
+type instancePoolAutoscaleEvent struct {
+	Time         time.Time `json:"time"`
+	InstancePool string    `json:"instance_pool"`
+	Zone         string    `json:"zone"`
+	Metric       *float64  `json:"metric,omitempty"`
+	Target       float64   `json:"target"`
+	Size         int64     `json:"size"`
+	Desired      int64     `json:"desired"`
+	Action       string    `json:"action"`
+	Reason       string    `json:"reason,omitempty"`
+	Evicted      []string  `json:"evicted,omitempty"`
+	DryRun       bool      `json:"dry_run,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+type instancePoolAutoscaleCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"autoscale"`
+
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Cooldown  int64   `cli-usage:"minimum time between two scaling operations (in seconds)"`
+	DryRun    bool    `cli-usage:"log scaling decisions without applying them"`
+	Interval  int64   `cli-usage:"metric evaluation interval (in seconds)"`
+	Max       int64   `cli-usage:"maximum Instance Pool size"`
+	MetricCmd string  `cli-flag:"metric-cmd" cli-usage:"command printing the current metric value on its standard output"`
+	MetricURL string  `cli-flag:"metric-url" cli-usage:"HTTP endpoint returning the current metric value"`
+	Min       int64   `cli-usage:"minimum Instance Pool size"`
+	Target    float64 `cli-usage:"metric target value"`
+	Tolerance float64 `cli-usage:"relative deviation of the metric from the target value tolerated before scaling"`
+	Zone      string  `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolAutoscaleCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolAutoscaleCmd) cmdShort() string { return "Autoscale an Instance Pool" }
+
+func (c *instancePoolAutoscaleCmd) cmdLong() string {
+	return `This command runs a client-side autoscaler for an Instance Pool in the
+foreground, until interrupted.
+
+At every interval, the autoscaler retrieves a metric value from a command
+(--metric-cmd, executed by the system shell) or an HTTP endpoint
+(--metric-url), which must return a number as the last word of its output.
+The metric is expected to be proportional to the Instance Pool load per
+member (e.g. average CPU usage): the desired size is computed as
+ceil(size * metric / target), bounded by --min and --max.
+
+To avoid flapping, no scaling occurs while the metric deviates from the
+target by less than --tolerance (relative), nor within --cooldown seconds
+after a scaling operation. When scaling down, the members unhealthy on the
+Network Load Balancer services targeting the Instance Pool are evicted first,
+then the most recently created ones.
+
+Every evaluation is logged on the standard output as a JSON object. Using
+--dry-run, scaling decisions are logged but not applied.
+
+Example:
+
+    exo compute instance-pool autoscale web --min 2 --max 10 \
+        --metric-cmd ./load.sh --target 0.7`
+}
+
+func (c *instancePoolAutoscaleCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if (c.MetricCmd == "") == (c.MetricURL == "") {
+		cmdExitOnUsageError(cmd, "exactly one of --metric-cmd or --metric-url must be specified")
+	}
+
+	if c.Target <= 0 {
+		cmdExitOnUsageError(cmd, "--target must be greater than 0")
+	}
+
+	if c.Min < 1 || c.Max < c.Min {
+		cmdExitOnUsageError(cmd, "--min must be greater than 0 and lower than or equal to --max")
+	}
+
+	if c.Interval < 1 {
+		cmdExitOnUsageError(cmd, "--interval must be greater than 0")
+	}
+
+	return nil
+}
+
+func (c *instancePoolAutoscaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	// The metric URL is requested using the account network settings (proxy, TLS).
+	transport, err := newHTTPTransport(gCurrentAccount)
+	if err != nil {
+		return fmt.Errorf("unable to initialize HTTP transport: %w", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	var (
+		enc       = json.NewEncoder(os.Stdout)
+		lastScale time.Time
+		ticker    = time.NewTicker(time.Duration(c.Interval) * time.Second)
+	)
+	defer ticker.Stop()
+
+	for {
+		event := c.evaluate(ctx, client, *instancePool.ID, lastScale)
+		if event.Action != instancePoolAutoscaleActionNone && event.Error == "" {
+			lastScale = event.Time
+		}
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+
+		select {
+		case <-gContext.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate retrieves the current Instance Pool size and metric value, and
+// scales the Instance Pool if required.
+func (c *instancePoolAutoscaleCmd) evaluate(
+	ctx context.Context,
+	client *http.Client,
+	instancePoolID string,
+	lastScale time.Time,
+) *instancePoolAutoscaleEvent {
+	event := &instancePoolAutoscaleEvent{
+		Time:         time.Now().UTC(),
+		InstancePool: c.InstancePool,
+		Zone:         c.Zone,
+		Target:       c.Target,
+		Action:       instancePoolAutoscaleActionNone,
+		DryRun:       c.DryRun,
+	}
+
+	instancePool, err := cs.GetInstancePool(ctx, c.Zone, instancePoolID)
+	if err != nil {
+		event.Error = fmt.Sprintf("unable to retrieve Instance Pool: %v", err)
+		return event
+	}
+	event.Size = *instancePool.Size
+	event.Desired = *instancePool.Size
+
+	// The size bounds are enforced regardless of the metric value and cooldown.
+	if event.Size < c.Min || event.Size > c.Max {
+		event.Desired, event.Reason = autoscaleDesiredSize(event.Size, c.Min, c.Max, c.Target, c.Target, c.Tolerance)
+	} else {
+		metric, err := c.metric(ctx, client)
+		if err != nil {
+			event.Error = fmt.Sprintf("unable to retrieve metric: %v", err)
+			return event
+		}
+		event.Metric = &metric
+
+		event.Desired, event.Reason = autoscaleDesiredSize(event.Size, c.Min, c.Max, metric, c.Target, c.Tolerance)
+		if event.Desired != event.Size && time.Since(lastScale) < time.Duration(c.Cooldown)*time.Second {
+			event.Reason = fmt.Sprintf("%s, in cooldown period", event.Reason)
+			event.Desired = event.Size
+		}
+	}
+
+	switch {
+	case event.Desired > event.Size:
+		event.Action = instancePoolAutoscaleActionScaleUp
+		if !c.DryRun {
+			if err := cs.ScaleInstancePool(ctx, c.Zone, instancePool, event.Desired); err != nil {
+				event.Error = fmt.Sprintf("unable to scale Instance Pool: %v", err)
+			}
+		}
+
+	case event.Desired < event.Size:
+		event.Action = instancePoolAutoscaleActionScaleDown
+		evicted, err := c.evictionCandidates(ctx, instancePool, event.Size-event.Desired)
+		if err != nil {
+			event.Error = fmt.Sprintf("unable to select members to evict: %v", err)
+			return event
+		}
+		event.Evicted = evicted
+		if !c.DryRun {
+			if err := cs.EvictInstancePoolMembers(ctx, c.Zone, instancePool, evicted); err != nil {
+				event.Error = fmt.Sprintf("unable to evict Instance Pool members: %v", err)
+			}
+		}
+	}
+
+	return event
+}
+
+// autoscaleDesiredSize returns the desired size of an Instance Pool of the
+// specified size based on the metric and target values, along with the
+// reason of the decision.
+func autoscaleDesiredSize(size, minSize, maxSize int64, metric, target, tolerance float64) (int64, string) {
+	switch {
+	case size < minSize:
+		return minSize, "size below minimum"
+	case size > maxSize:
+		return maxSize, "size above maximum"
+	}
+
+	ratio := metric / target
+	if math.Abs(ratio-1) <= tolerance {
+		return size, "metric within tolerance"
+	}
+
+	desired := int64(math.Ceil(float64(size) * ratio))
+	if desired < minSize {
+		desired = minSize
+	}
+	if desired > maxSize {
+		desired = maxSize
+	}
+
+	if ratio > 1 {
+		return desired, "metric above target"
+	}
+	return desired, "metric below target"
+}
+
+// metric returns the current metric value, using the HTTP client specified
+// to request the metric URL.
+func (c *instancePoolAutoscaleCmd) metric(ctx context.Context, client *http.Client) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Interval)*time.Second)
+	defer cancel()
+
+	var out []byte
+
+	if c.MetricCmd != "" {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+
+		cmd := exec.CommandContext(ctx, shell, flag, c.MetricCmd)
+		cmd.Stderr = os.Stderr
+
+		var err error
+		if out, err = cmd.Output(); err != nil {
+			return 0, err
+		}
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.MetricURL, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+
+		if out, err = io.ReadAll(io.LimitReader(resp.Body, 4096)); err != nil {
+			return 0, err
+		}
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, errors.New("empty output")
+	}
+
+	v, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid metric value: %w", err)
+	}
+
+	return v, nil
+}
+
+// evictionCandidates returns the IDs of the n Instance Pool members to evict
+// first: members unhealthy on the Network Load Balancer services targeting
+// the Instance Pool, then members not running, then the most recently created
+// members.
+func (c *instancePoolAutoscaleCmd) evictionCandidates(
+	ctx context.Context,
+	instancePool *egoscale.InstancePool,
+	n int64,
+) ([]string, error) {
+	health, err := instancePoolNLBHealth(ctx, c.Zone, *instancePool.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		id        string
+		unhealthy bool
+		running   bool
+		createdAt time.Time
+	}
+
+	candidates := make([]candidate, 0)
+	if instancePool.InstanceIDs != nil {
+		for _, id := range *instancePool.InstanceIDs {
+			instance, err := cs.GetInstance(ctx, c.Zone, id)
+			if err != nil {
+				return nil, err
+			}
+
+			cand := candidate{
+				id:      id,
+				running: *instance.State == string(oapi.InstanceStateRunning),
+			}
+			if instance.CreatedAt != nil {
+				cand.createdAt = *instance.CreatedAt
+			}
+			if health != nil && instance.PublicIPAddress != nil {
+				cand.unhealthy = !health[instance.PublicIPAddress.String()]
+			}
+			candidates = append(candidates, cand)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		switch {
+		case candidates[i].unhealthy != candidates[j].unhealthy:
+			return candidates[i].unhealthy
+		case candidates[i].running != candidates[j].running:
+			return !candidates[i].running
+		default:
+			return candidates[i].createdAt.After(candidates[j].createdAt)
+		}
+	})
+
+	if int64(len(candidates)) > n {
+		candidates = candidates[:n]
+	}
+
+	ids := make([]string, len(candidates))
+	for i := range candidates {
+		ids[i] = candidates[i].id
+	}
+
+	return ids, nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolAutoscaleCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+
+		Cooldown:  300,
+		Interval:  60,
+		Min:       1,
+		Tolerance: 0.1,
+	}))
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_autoscaleDesiredSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int64
+		metric   float64
+		expected int64
+	}{
+		{name: "below minimum", size: 1, metric: 0.7, expected: 2},
+		{name: "above maximum", size: 12, metric: 0.7, expected: 10},
+		{name: "within tolerance", size: 4, metric: 0.75, expected: 4},
+		{name: "scale up", size: 4, metric: 0.9, expected: 6},
+		{name: "scale up bounded", size: 8, metric: 1.4, expected: 10},
+		{name: "scale down", size: 4, metric: 0.35, expected: 2},
+		{name: "scale down bounded", size: 4, metric: 0.1, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, _ := autoscaleDesiredSize(tt.size, 2, 10, tt.metric, 0.7, 0.1)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -18,8 +18,9 @@ type instancePoolCreateCmd struct {
 	Name string `cli-arg:"#" cli-usage:"NAME"`
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-short:"a" cli-usage:"managed Compute instances Anti-Affinity Group NAME|ID (can be specified multiple times)"`
-	CloudInitFile      string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path"`
+	CloudInitFile      []string          `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path (can be specified multiple times)"`
 	CloudInitCompress  bool              `cli-flag:"cloud-init-compress" cli-usage:"compress instance cloud-init user data"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
 	DeployTarget       string            `cli-usage:"managed Compute instances Deploy Target NAME|ID"`
 	Description        string            `cli-usage:"Instance Pool description"`
 	Disk               int64             `cli-flag:"disk" cli-short:"d" cli-usage:"[DEPRECATED] use --disk-size"`
@@ -49,7 +50,10 @@ func (c *instancePoolCreateCmd) cmdShort() string { return "Create an Instance P
 func (c *instancePoolCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates an Instance Pool.
 
+%s
+
 Supported output template annotations: %s`,
+		cloudInitHelp,
 		strings.Join(outputterTemplateAnnotations(&instancePoolShowOutput{}), ", "))
 }
 
@@ -233,8 +237,12 @@ func (c *instancePoolCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	}
 	instancePool.TemplateID = template.ID
 
-	if c.CloudInitFile != "" {
-		userData, err := getUserDataFromFile(c.CloudInitFile, c.CloudInitCompress)
+	if len(c.CloudInitFile) > 0 {
+		userData, err := buildUserData(c.CloudInitFile, &cloudInitTemplateData{
+			Name: c.Name,
+			Zone: c.Zone,
+			Vars: c.CloudInitVars,
+		}, c.CloudInitCompress)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %w", err)
 		}
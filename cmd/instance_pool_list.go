@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -47,12 +48,16 @@ func (c *instancePoolListCmd) cmdPreRun(cmd *cobra.Command, args []string) error
 }
 
 func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *instancePoolListCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
 	var zones []string
 
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(client, acc)
 	}
 
 	out := make(instancePoolListOutput, 0)
@@ -66,9 +71,9 @@ func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		done <- struct{}{}
 	}()
 	err := forEachZone(zones, func(zone string) error {
-		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, zone))
 
-		list, err := cs.ListInstancePools(ctx, zone)
+		list, err := client.ListInstancePools(ctx, zone)
 		if err != nil {
 			return fmt.Errorf("unable to list Instance Pools in zone %s: %w", zone, err)
 		}
@@ -93,7 +98,7 @@ func (c *instancePoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	close(res)
 	<-done
 
-	return c.outputFunc(&out, nil)
+	return &out, nil
 }
 
 func init() {
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instancePoolMembersItemOutput struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	State           string   `json:"state"`
+	IPAddress       string   `json:"ip_address"`
+	IPv6Address     string   `json:"ipv6_address" outputLabel:"IPv6 Address"`
+	PrivateNetworks []string `json:"private_networks"`
+	Template        string   `json:"template"`
+	CreationDate    string   `json:"creation_date"`
+	Drift           []string `json:"drift"`
+}
+
+type instancePoolMembersOutput []instancePoolMembersItemOutput
+
+func (o *instancePoolMembersOutput) toJSON()  { outputJSON(o) }
+func (o *instancePoolMembersOutput) toText()  { outputText(o) }
+func (o *instancePoolMembersOutput) toTable() { outputTable(o) }
+
+type instancePoolMembersCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"members"`
+
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Zone string `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolMembersCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolMembersCmd) cmdShort() string { return "List Instance Pool members" }
+
+func (c *instancePoolMembersCmd) cmdLong() string {
+	return fmt.Sprintf(`This command lists the members of an Instance Pool.
+
+The Drift column lists the properties of each member that don't match the
+current Instance Pool configuration anymore (template, instance-type,
+disk-size and user-data): such members can be replaced using the
+"exo compute instance-pool rollout" command.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instancePoolMembersItemOutput{}), ", "))
+}
+
+func (c *instancePoolMembersCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instancePoolMembersCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	var (
+		out   = make(instancePoolMembersOutput, 0)
+		cache = newResourceCache()
+		ids   = make([]string, 0)
+		mu    sync.Mutex
+	)
+
+	if instancePool.InstanceIDs != nil {
+		ids = *instancePool.InstanceIDs
+	}
+
+	err = forEachParallel(len(ids), func(i int) error {
+		id := ids[i]
+
+		instance, err := cs.GetInstance(ctx, c.Zone, id)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve instance %s: %w", id, err)
+		}
+
+		item := instancePoolMembersItemOutput{
+			ID:              *instance.ID,
+			Name:            *instance.Name,
+			State:           *instance.State,
+			IPAddress:       utils.DefaultIP(instance.PublicIPAddress, emptyIPAddressVisualization),
+			IPv6Address:     utils.DefaultIP(instance.IPv6Address, emptyIPAddressVisualization),
+			PrivateNetworks: make([]string, 0),
+			Template:        *instance.TemplateID,
+			Drift:           instancePoolMemberDrift(instancePool, instance),
+		}
+
+		// Members still being created might not have a creation date yet.
+		if instance.CreatedAt != nil {
+			item.CreationDate = instance.CreatedAt.String()
+		}
+
+		if template, err := cache.template(ctx, cs.Client, c.Zone, *instance.TemplateID); err == nil {
+			item.Template = *template.Name
+		}
+
+		if instance.PrivateNetworkIDs != nil {
+			for _, pnID := range *instance.PrivateNetworkIDs {
+				privateNetwork, err := cache.privateNetwork(ctx, cs.Client, c.Zone, pnID)
+				if err != nil {
+					return fmt.Errorf("unable to retrieve Private Network %s: %w", pnID, err)
+				}
+
+				pn := *privateNetwork.Name
+				for _, lease := range privateNetwork.Leases {
+					if *lease.InstanceID == *instance.ID {
+						pn = fmt.Sprintf("%s:%s", pn, lease.IPAddress)
+					}
+				}
+				item.PrivateNetworks = append(item.PrivateNetworks, pn)
+			}
+		}
+
+		mu.Lock()
+		out = append(out, item)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return c.outputFunc(&out, nil)
+}
+
+// instancePoolMemberDrift returns the list of properties of an Instance Pool
+// member that don't match the current Instance Pool configuration.
+func instancePoolMemberDrift(instancePool *egoscale.InstancePool, instance *egoscale.Instance) []string {
+	drift := make([]string, 0)
+
+	if utils.DefaultString(instancePool.TemplateID, "") != utils.DefaultString(instance.TemplateID, "") {
+		drift = append(drift, "template")
+	}
+
+	if utils.DefaultString(instancePool.InstanceTypeID, "") != utils.DefaultString(instance.InstanceTypeID, "") {
+		drift = append(drift, "instance-type")
+	}
+
+	if instancePool.DiskSize != nil && instance.DiskSize != nil && *instancePool.DiskSize != *instance.DiskSize {
+		drift = append(drift, "disk-size")
+	}
+
+	if utils.DefaultString(instancePool.UserData, "") != utils.DefaultString(instance.UserData, "") {
+		drift = append(drift, "user-data")
+	}
+
+	return drift
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolMembersCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
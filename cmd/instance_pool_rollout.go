@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+// instancePoolRolloutPollInterval is the interval at which the new members
+// of an Instance Pool are checked during a rollout.
+const instancePoolRolloutPollInterval = 10 * time.Second
+
+// instancePoolRolloutState represents the state of an Instance Pool rollout,
+// persisted on disk between batches so that it can be resumed or aborted.
+type instancePoolRolloutState struct {
+	// Pending lists the IDs of the members remaining to be replaced.
+	Pending []string `json:"pending"`
+	// Baseline lists the IDs of the members before the Instance Pool has been
+	// scaled up for the current batch, if any.
+	Baseline []string `json:"baseline,omitempty"`
+}
+
+// surge returns the IDs of the members added to the Instance Pool for the
+// current batch.
+func (s *instancePoolRolloutState) surge(instancePool *egoscale.InstancePool) []string {
+	surge := make([]string, 0)
+	if s.Baseline == nil || instancePool.InstanceIDs == nil {
+		return surge
+	}
+
+	baseline := make(map[string]struct{})
+	for _, id := range s.Baseline {
+		baseline[id] = struct{}{}
+	}
+	for _, id := range *instancePool.InstanceIDs {
+		if _, ok := baseline[id]; !ok {
+			surge = append(surge, id)
+		}
+	}
+
+	return surge
+}
+
+func instancePoolRolloutStatePath(id string) string {
+	return path.Join(gConfigFolder, "instance-pools", id, "rollout.json")
+}
+
+// loadInstancePoolRolloutState returns the state of the rollout in progress
+// for the specified Instance Pool, or nil if there is none.
+func loadInstancePoolRolloutState(id string) (*instancePoolRolloutState, error) {
+	data, err := os.ReadFile(instancePoolRolloutStatePath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state instancePoolRolloutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid rollout state file: %w", err)
+	}
+
+	return &state, nil
+}
+
+func (s *instancePoolRolloutState) save(id string) error {
+	statePath := instancePoolRolloutStatePath(id)
+
+	if err := os.MkdirAll(path.Dir(statePath), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(statePath, data, 0o600)
+}
+
+func (s *instancePoolRolloutState) delete(id string) error {
+	return os.RemoveAll(path.Dir(instancePoolRolloutStatePath(id)))
+}
+
+type instancePoolRolloutCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"rollout"`
+
+	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Abort       bool   `cli-usage:"abort the rollout in progress"`
+	BatchSize   int64  `cli-usage:"number of members to replace at once"`
+	Force       bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Resume      bool   `cli-usage:"resume the rollout in progress"`
+	Timeout     int64  `cli-usage:"maximum time to wait for a batch of new members to be ready (in seconds)"`
+	WaitHealthy bool   `cli-usage:"wait for new members to be healthy on the Network Load Balancer services targeting the Instance Pool"`
+	Zone        string `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolRolloutCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolRolloutCmd) cmdShort() string {
+	return "Replace the members of an Instance Pool"
+}
+
+func (c *instancePoolRolloutCmd) cmdLong() string {
+	return `This command replaces the members of an Instance Pool batch by batch, so that
+they pick up changes made to the Instance Pool configuration (e.g. using the
+"exo compute instance-pool update" command with the --template or
+--cloud-init flags).
+
+For each batch, the Instance Pool is scaled up by the batch size, then once
+the new members are running (and healthy on the Network Load Balancer
+services targeting the Instance Pool if --wait-healthy is set) the old
+members of the batch are evicted.
+
+If a batch fails, the rollout stops and can be continued using the --resume
+flag once the problem is fixed, or cancelled using the --abort flag which
+evicts the members created for the failed batch (members already replaced are
+kept).`
+}
+
+func (c *instancePoolRolloutCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Abort && c.Resume {
+		cmdExitOnUsageError(cmd, "--abort and --resume are mutually exclusive")
+	}
+
+	if c.BatchSize < 1 {
+		cmdExitOnUsageError(cmd, "--batch-size must be greater than 0")
+	}
+
+	if c.Timeout < 1 {
+		cmdExitOnUsageError(cmd, "--timeout must be greater than 0")
+	}
+
+	return nil
+}
+
+func (c *instancePoolRolloutCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	state, err := loadInstancePoolRolloutState(*instancePool.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c.Abort || c.Resume:
+		if state == nil {
+			return fmt.Errorf("no rollout in progress for Instance Pool %q", c.InstancePool)
+		}
+
+		if c.Abort {
+			return c.abort(ctx, instancePool, state)
+		}
+
+	case state != nil:
+		return fmt.Errorf(
+			"a rollout is already in progress for Instance Pool %q, use --resume to continue it or --abort to cancel it",
+			c.InstancePool,
+		)
+
+	default:
+		if instancePool.InstanceIDs == nil || len(*instancePool.InstanceIDs) == 0 {
+			return fmt.Errorf("no members in Instance Pool %q", c.InstancePool)
+		}
+
+		if !c.Force {
+			if !askQuestion(fmt.Sprintf(
+				"Are you sure you want to replace the %d members of Instance Pool %q?",
+				len(*instancePool.InstanceIDs),
+				c.InstancePool,
+			)) {
+				return nil
+			}
+		}
+
+		state = &instancePoolRolloutState{Pending: *instancePool.InstanceIDs}
+		if err := state.save(*instancePool.ID); err != nil {
+			return fmt.Errorf("unable to save rollout state: %w", err)
+		}
+	}
+
+	if err := c.rollout(ctx, instancePool, state); err != nil {
+		return fmt.Errorf(
+			"%w\nThe rollout has been interrupted, run the command with --resume to continue it or with --abort to cancel it",
+			err,
+		)
+	}
+
+	if err := state.delete(*instancePool.ID); err != nil {
+		return fmt.Errorf("unable to delete rollout state: %w", err)
+	}
+
+	if !gQuiet {
+		return (&instancePoolShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Zone:               c.Zone,
+			InstancePool:       *instancePool.ID,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+// rollout replaces the pending members of the Instance Pool batch by batch,
+// saving the rollout state after each step.
+func (c *instancePoolRolloutCmd) rollout(
+	ctx context.Context,
+	instancePool *egoscale.InstancePool,
+	state *instancePoolRolloutState,
+) error {
+	var err error
+
+	for {
+		if instancePool, err = cs.GetInstancePool(ctx, c.Zone, *instancePool.ID); err != nil {
+			return err
+		}
+
+		members := make(map[string]struct{})
+		if instancePool.InstanceIDs != nil {
+			for _, id := range *instancePool.InstanceIDs {
+				members[id] = struct{}{}
+			}
+		}
+
+		// Members removed from the Instance Pool since the beginning of the
+		// rollout don't need to be replaced anymore.
+		pending := make([]string, 0)
+		for _, id := range state.Pending {
+			if _, ok := members[id]; ok {
+				pending = append(pending, id)
+			}
+		}
+		state.Pending = pending
+
+		if len(state.Pending) == 0 {
+			return nil
+		}
+
+		batch := state.Pending
+		if int64(len(batch)) > c.BatchSize {
+			batch = batch[:c.BatchSize]
+		}
+
+		// The baseline is recorded before scaling up the Instance Pool, so that
+		// a resumed rollout doesn't scale it up again for the same batch.
+		if state.Baseline == nil {
+			state.Baseline = make([]string, 0, len(members))
+			for id := range members {
+				state.Baseline = append(state.Baseline, id)
+			}
+			if err := state.save(*instancePool.ID); err != nil {
+				return fmt.Errorf("unable to save rollout state: %w", err)
+			}
+		}
+
+		size := int64(len(state.Baseline) + len(batch))
+		decorateAsyncOperation(
+			fmt.Sprintf("Scaling Instance Pool %q up to %d members...", *instancePool.Name, size),
+			func() {
+				if *instancePool.Size < size {
+					if err = cs.ScaleInstancePool(ctx, c.Zone, instancePool, size); err != nil {
+						return
+					}
+				}
+				err = cs.WaitInstancePoolConverged(ctx, c.Zone, *instancePool.ID)
+			})
+		if err != nil {
+			return fmt.Errorf("unable to scale Instance Pool: %w", err)
+		}
+
+		if instancePool, err = cs.GetInstancePool(ctx, c.Zone, *instancePool.ID); err != nil {
+			return err
+		}
+		surge := state.surge(instancePool)
+
+		decorateAsyncOperation(
+			fmt.Sprintf("Waiting for %d new members to be ready...", len(surge)),
+			func() { err = c.waitMembersReady(ctx, instancePool, surge) },
+		)
+		if err != nil {
+			return err
+		}
+
+		decorateAsyncOperation(
+			fmt.Sprintf("Evicting %d old members...", len(batch)),
+			func() { err = cs.EvictInstancePoolMembers(ctx, c.Zone, instancePool, batch) },
+		)
+		if err != nil {
+			return fmt.Errorf("unable to evict Instance Pool members: %w", err)
+		}
+
+		state.Pending = state.Pending[len(batch):]
+		state.Baseline = nil
+		if err := state.save(*instancePool.ID); err != nil {
+			return fmt.Errorf("unable to save rollout state: %w", err)
+		}
+	}
+}
+
+// waitMembersReady waits until the specified Instance Pool members are
+// running, and healthy on the Network Load Balancer services targeting the
+// Instance Pool if requested.
+func (c *instancePoolRolloutCmd) waitMembersReady(
+	ctx context.Context,
+	instancePool *egoscale.InstancePool,
+	ids []string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	for {
+		ready, err := c.membersReady(ctx, instancePool, ids)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("new members not ready after %ds", c.Timeout)
+			}
+			return ctx.Err()
+		case <-time.After(instancePoolRolloutPollInterval):
+		}
+	}
+}
+
+func (c *instancePoolRolloutCmd) membersReady(
+	ctx context.Context,
+	instancePool *egoscale.InstancePool,
+	ids []string,
+) (bool, error) {
+	addresses := make([]string, 0)
+	for _, id := range ids {
+		instance, err := cs.GetInstance(ctx, c.Zone, id)
+		if err != nil {
+			return false, err
+		}
+
+		switch *instance.State {
+		case string(oapi.InstanceStateRunning):
+		case string(oapi.InstanceStateError):
+			return false, fmt.Errorf("instance %q is in error state", *instance.Name)
+		default:
+			return false, nil
+		}
+
+		if instance.PublicIPAddress != nil {
+			addresses = append(addresses, instance.PublicIPAddress.String())
+		}
+	}
+
+	if !c.WaitHealthy {
+		return true, nil
+	}
+
+	health, err := instancePoolNLBHealth(ctx, c.Zone, *instancePool.ID)
+	if err != nil {
+		return false, err
+	}
+	if health == nil {
+		return false, fmt.Errorf("no Network Load Balancer service targets Instance Pool %q", *instancePool.Name)
+	}
+
+	for _, address := range addresses {
+		if !health[address] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// abort cancels the rollout in progress, evicting the members created for
+// the current batch if any.
+func (c *instancePoolRolloutCmd) abort(
+	ctx context.Context,
+	instancePool *egoscale.InstancePool,
+	state *instancePoolRolloutState,
+) error {
+	if !c.Force {
+		if !askQuestion(fmt.Sprintf("Are you sure you want to abort the rollout of Instance Pool %q?", c.InstancePool)) {
+			return nil
+		}
+	}
+
+	if surge := state.surge(instancePool); len(surge) > 0 {
+		var err error
+		decorateAsyncOperation(
+			fmt.Sprintf("Evicting %d new members...", len(surge)),
+			func() { err = cs.EvictInstancePoolMembers(ctx, c.Zone, instancePool, surge) },
+		)
+		if err != nil {
+			return fmt.Errorf("unable to evict Instance Pool members: %w", err)
+		}
+	}
+
+	if err := state.delete(*instancePool.ID); err != nil {
+		return fmt.Errorf("unable to delete rollout state: %w", err)
+	}
+
+	if !gQuiet {
+		return (&instancePoolShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Zone:               c.Zone,
+			InstancePool:       *instancePool.ID,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolCmd, &instancePoolRolloutCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+
+		BatchSize: 1,
+		Timeout:   600,
+	}))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,7 @@ type instancePoolScaleCmd struct {
 	InstancePool string `cli-arg:"#" cli-usage:"INSTANCE-POOL-NAME|ID"`
 	Size         int64  `cli-arg:"#"`
 
+	Async bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
 	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
 	Zone  string `cli-short:"z" cli-usage:"Instance Pool zone"`
 }
@@ -64,6 +66,24 @@ func (c *instancePoolScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if c.Async {
+		resp, err := cs.ScaleInstancePoolWithResponse(
+			ctx,
+			*instancePool.ID,
+			oapi.ScaleInstancePoolJSONRequestBody{Size: c.Size},
+		)
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+	}
+
 	decorateAsyncOperation(fmt.Sprintf("Scaling Instance Pool %q...", c.InstancePool), func() {
 		err = cs.ScaleInstancePool(ctx, c.Zone, instancePool, c.Size)
 	})
@@ -18,8 +18,9 @@ type instancePoolUpdateCmd struct {
 	InstancePool string `cli-arg:"#" cli-usage:"NAME|ID"`
 
 	AntiAffinityGroups []string          `cli-flag:"anti-affinity-group" cli-short:"a" cli-usage:"managed Compute instances Anti-Affinity Group NAME|ID (can be specified multiple times)"`
-	CloudInitFile      string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path"`
+	CloudInitFile      []string          `cli-flag:"cloud-init" cli-short:"c" cli-usage:"cloud-init user data configuration file path (can be specified multiple times)"`
 	CloudInitCompress  bool              `cli-flag:"cloud-init-compress" cli-usage:"compress instance cloud-init user data"`
+	CloudInitVars      map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
 	DeployTarget       string            `cli-usage:"managed Compute instances Deploy Target NAME|ID"`
 	Description        string            `cli-usage:"Instance Pool description"`
 	Disk               int64             `cli-flag:"disk" cli-short:"d" cli-usage:"[DEPRECATED] use --disk-size"`
@@ -50,7 +51,10 @@ func (c *instancePoolUpdateCmd) cmdShort() string { return "Update an Instance P
 func (c *instancePoolUpdateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command updates an Instance Pool.
 
+%s
+
 Supported output template annotations: %s`,
+		cloudInitHelp,
 		strings.Join(outputterTemplateAnnotations(&instancePoolShowOutput{}), ", "),
 	)
 }
@@ -271,7 +275,11 @@ func (c *instancePoolUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CloudInitFile)) {
-		userData, err := getUserDataFromFile(c.CloudInitFile, c.CloudInitCompress)
+		userData, err := buildUserData(c.CloudInitFile, &cloudInitTemplateData{
+			Name: *instancePool.Name,
+			Zone: c.Zone,
+			Vars: c.CloudInitVars,
+		}, c.CloudInitCompress)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %w", err)
 		}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+var instancePoolUserDataCmd = &cobra.Command{
+	Use:   "user-data",
+	Short: "Manage Instance Pools cloud-init user data",
+}
+
+func init() {
+	instancePoolCmd.AddCommand(instancePoolUserDataCmd)
+}
+
+type instancePoolUserDataDiffCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"diff"`
+
+	InstancePool  string   `cli-arg:"#" cli-usage:"NAME|ID"`
+	CloudInitFile []string `cli-arg:"#" cli-usage:"FILE"`
+
+	CloudInitVars map[string]string `cli-flag:"cloud-init-var" cli-usage:"cloud-init user data template variable (format: key=value)"`
+	Zone          string            `cli-short:"z" cli-usage:"Instance Pool zone"`
+}
+
+func (c *instancePoolUserDataDiffCmd) cmdAliases() []string { return nil }
+
+func (c *instancePoolUserDataDiffCmd) cmdShort() string {
+	return "Compare an Instance Pool cloud-init user data with local files"
+}
+
+func (c *instancePoolUserDataDiffCmd) cmdLong() string {
+	return `This command compares the cloud-init user data an Instance Pool's members
+are created with to the user data rendered from local files, the same way the
+"exo compute instance-pool update --cloud-init" command would render them
+(see "exo compute instance-pool update --help"). Differences are printed as a
+unified diff, and the command exits with a non-zero status if any is found.`
+}
+
+func (c *instancePoolUserDataDiffCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instancePoolUserDataDiffCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instancePool, err := cs.FindInstancePool(ctx, c.Zone, c.InstancePool)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	var current string
+	if instancePool.UserData != nil && *instancePool.UserData != "" {
+		if current, err = decodeUserData(*instancePool.UserData); err != nil {
+			return fmt.Errorf("error decoding Instance Pool user data: %w", err)
+		}
+	}
+
+	local, err := renderUserData(c.CloudInitFile, &cloudInitTemplateData{
+		Name: *instancePool.Name,
+		Zone: c.Zone,
+		Vars: c.CloudInitVars,
+	})
+	if err != nil {
+		return fmt.Errorf("error parsing cloud-init user data: %w", err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(current),
+		B:        difflib.SplitLines(string(local)),
+		FromFile: fmt.Sprintf("%s (current)", *instancePool.Name),
+		ToFile:   fmt.Sprintf("%s (local)", *instancePool.Name),
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		if !gQuiet {
+			fmt.Println("No differences found.")
+		}
+		return nil
+	}
+
+	fmt.Print(diff)
+
+	return fmt.Errorf("user data of Instance Pool %q differ from local files", *instancePool.Name)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instancePoolUserDataCmd, &instancePoolUserDataDiffCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
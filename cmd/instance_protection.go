@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+var instanceProtectionCmd = &cobra.Command{
+	Use:   "protection",
+	Short: "Manage Compute instances deletion protection",
+}
+
+func init() {
+	instanceCmd.AddCommand(instanceProtectionCmd)
+}
+
+// getInstanceDeletionProtection returns true if the deletion protection is
+// enabled on the specified Compute instance.
+func getInstanceDeletionProtection(ctx context.Context, client *exov2.Client, id string) (bool, error) {
+	resp, err := client.GetInstanceWithResponse(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return false, fmt.Errorf("API request error: unexpected status %s", resp.Status())
+	}
+
+	// The deletion protection status is not exposed by the egoscale
+	// Instance type, we have to extract it from the raw API response.
+	var res struct {
+		DeletionProtection bool `json:"deletion-protection"`
+	}
+	if err := json.Unmarshal(resp.Body, &res); err != nil {
+		return false, fmt.Errorf("unable to decode API response: %w", err)
+	}
+
+	return res.DeletionProtection, nil
+}
+
+// setInstanceDeletionProtection enables or disables the deletion protection
+// of the specified Compute instance.
+func setInstanceDeletionProtection(ctx context.Context, id string, enable bool) error {
+	var (
+		statusCode int
+		status     string
+		body       []byte
+	)
+
+	if enable {
+		resp, err := cs.AddInstanceProtectionWithResponse(ctx, id)
+		if err != nil {
+			return err
+		}
+		statusCode, status, body = resp.StatusCode(), resp.Status(), resp.Body
+	} else {
+		resp, err := cs.RemoveInstanceProtectionWithResponse(ctx, id)
+		if err != nil {
+			return err
+		}
+		statusCode, status, body = resp.StatusCode(), resp.Status(), resp.Body
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("API request error: unexpected status %s", status)
+	}
+
+	var op oapi.Operation
+	if err := json.Unmarshal(body, &op); err != nil || op.Id == nil {
+		return nil
+	}
+
+	res, err := waitAsyncOperation(ctx, *op.Id, time.Minute*time.Duration(gCurrentAccount.ClientTimeout))
+	if err != nil {
+		return err
+	}
+	if res.State != nil && *res.State != oapi.OperationStateSuccess {
+		return fmt.Errorf("operation %s %s", *op.Id, *res.State)
+	}
+
+	return nil
+}
+
+// checkInstanceDeletionProtection returns an error explaining how to proceed
+// if the deletion protection is enabled on the specified Compute instance.
+func checkInstanceDeletionProtection(ctx context.Context, instance *exov2.Instance) error {
+	protected, err := getInstanceDeletionProtection(ctx, cs.Client, *instance.ID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve instance deletion protection status: %w", err)
+	}
+
+	if protected {
+		return fmt.Errorf(
+			"instance %q is protected against deletion, "+
+				"run \"exo compute instance protection remove %s\" to disable the protection first",
+			*instance.Name,
+			*instance.ID,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceProtectionAddCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"add"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Zone string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceProtectionAddCmd) cmdAliases() []string { return nil }
+
+func (c *instanceProtectionAddCmd) cmdShort() string {
+	return "Enable a Compute instance deletion protection"
+}
+
+func (c *instanceProtectionAddCmd) cmdLong() string {
+	return `This command enables the deletion protection of a Compute instance: as long as
+it is enabled, the instance cannot be deleted.`
+}
+
+func (c *instanceProtectionAddCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceProtectionAddCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	decorateAsyncOperation(fmt.Sprintf("Enabling instance %q deletion protection...", c.Instance), func() {
+		err = setInstanceDeletionProtection(ctx, *instance.ID, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return (&instanceShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Instance:           *instance.ID,
+			Zone:               c.Zone,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceProtectionCmd, &instanceProtectionAddCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
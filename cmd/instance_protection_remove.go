@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceProtectionRemoveCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"remove"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Zone string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceProtectionRemoveCmd) cmdAliases() []string { return gRemoveAlias }
+
+func (c *instanceProtectionRemoveCmd) cmdShort() string {
+	return "Disable a Compute instance deletion protection"
+}
+
+func (c *instanceProtectionRemoveCmd) cmdLong() string {
+	return `This command disables the deletion protection of a Compute instance, allowing
+it to be deleted.`
+}
+
+func (c *instanceProtectionRemoveCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceProtectionRemoveCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	decorateAsyncOperation(fmt.Sprintf("Disabling instance %q deletion protection...", c.Instance), func() {
+		err = setInstanceDeletionProtection(ctx, *instance.ID, false)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return (&instanceShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Instance:           *instance.ID,
+			Zone:               c.Zone,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceProtectionCmd, &instanceProtectionRemoveCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
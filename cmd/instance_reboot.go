@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -13,26 +14,58 @@ type instanceRebootCmd struct {
 
 	_ bool `cli-cmd:"reboot"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force    bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Selector string `cli-short:"l" cli-usage:"label selector of the instances to reboot (e.g. env=prod,role!=db)"`
+	Zone     string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceRebootCmd) cmdAliases() []string { return nil }
 
 func (c *instanceRebootCmd) cmdShort() string { return "Reboot a Compute instance" }
 
-func (c *instanceRebootCmd) cmdLong() string { return "" }
+func (c *instanceRebootCmd) cmdLong() string {
+	return fmt.Sprintf(`This command reboots a Compute instance, or all the Compute instances of
+the zone matching the label selector specified using the --selector flag.
+
+%s
+
+Example:
+
+    exo compute instance reboot --selector env=staging,role!=db
+`,
+		labelSelectorHelp)
+}
 
 func (c *instanceRebootCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+	return validateInstanceOrSelector(c.Instance, c.Selector)
 }
 
 func (c *instanceRebootCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	if c.Selector != "" {
+		instances, err := findInstancesBySelector(ctx, c.Zone, c.Selector)
+		if err != nil {
+			return err
+		}
+
+		if !c.Force {
+			if !askBulkInstanceQuestion("reboot", instances) {
+				return nil
+			}
+		}
+
+		return bulkInstanceOperation("Rebooting instance", instances, func(instance *egoscale.Instance) error {
+			return cs.RebootInstance(ctx, c.Zone, instance)
+		})
+	}
+
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
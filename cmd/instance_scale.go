@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -14,11 +15,12 @@ type instanceScaleCmd struct {
 
 	_ bool `cli-cmd:"scale"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
-	Type     string `cli-arg:"#" cli-usage:"SIZE"`
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
+	Type     string `cli-arg:"?" cli-usage:"SIZE"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force    bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Selector string `cli-short:"l" cli-usage:"label selector of the instances to scale (e.g. env=prod,role!=db)"`
+	Zone     string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceScaleCmd) cmdAliases() []string { return nil }
@@ -28,6 +30,11 @@ func (c *instanceScaleCmd) cmdShort() string { return "Scale a Compute instance"
 func (c *instanceScaleCmd) cmdLong() string {
 	return fmt.Sprintf(`This commands scales a Compute instance to a different size.
 
+Using the --selector flag, all the Compute instances of the zone matching the
+label selector are scaled instead, and only the SIZE argument is expected:
+
+    exo compute instance scale --selector role=web large
+
 Supported Compute instance type sizes: %s
 
 Supported output template annotations: %s`,
@@ -37,12 +44,48 @@ Supported output template annotations: %s`,
 
 func (c *instanceScaleCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	// When targeting instances using a label selector, the only positional
+	// argument expected is the instance type SIZE.
+	if c.Selector != "" && len(args) == 1 {
+		c.Instance, c.Type = "", c.Instance
+	}
+
+	if c.Type == "" {
+		return fmt.Errorf("missing arguments, run with --help for usage")
+	}
+
+	return validateInstanceOrSelector(c.Instance, c.Selector)
 }
 
 func (c *instanceScaleCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	if c.Selector != "" {
+		instances, err := findInstancesBySelector(ctx, c.Zone, c.Selector)
+		if err != nil {
+			return err
+		}
+
+		if !c.Force {
+			if !askBulkInstanceQuestion("scale", instances) {
+				return nil
+			}
+		}
+
+		instanceType, err := cs.FindInstanceType(ctx, c.Zone, c.Type)
+		if err != nil {
+			return fmt.Errorf("error retrieving instance type: %w", err)
+		}
+
+		return bulkInstanceOperation("Scaling instance", instances, func(instance *egoscale.Instance) error {
+			return cs.ScaleInstance(ctx, c.Zone, instance, instanceType)
+		})
+	}
+
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
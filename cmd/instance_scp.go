@@ -18,6 +18,7 @@ type instanceSCPCmd struct {
 	scpInfo struct {
 		ipAddress string
 		keyFile   string
+		proxyJump string
 	} `cli-cmd:"-"`
 	_ bool `cli-cmd:"scp"`
 
@@ -25,13 +26,15 @@ type instanceSCPCmd struct {
 	Source   string `cli-arg:"#"`
 	Target   string `cli-arg:"#"`
 
-	IPv6      bool   `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
-	Login     string `cli-short:"l" cli-help:"SCP username to use for logging in (default: instance template default username)"`
-	PrintCmd  bool   `cli-flag:"print-command" cli-usage:"print the SCP command that would be executed instead of executing it"`
-	Recursive bool   `cli-short:"r" cli-usage:"recursively copy entire directories"`
-	ReplStr   string `cli-flag:"replace-str" cli-short:"i" cli-usage:"string to replace with the actual Compute instance information (i.e. username@IP-ADDRESS)"`
-	SCPOpts   string `cli-flag:"scp-options" cli-short:"o" cli-usage:"additional options to pass to the scp(1) command"`
-	Zone      string `cli-short:"z" cli-usage:"instance zone"`
+	IPv6           bool   `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
+	Login          string `cli-short:"l" cli-help:"SCP username to use for logging in (default: instance template default username)"`
+	PrintCmd       bool   `cli-flag:"print-command" cli-usage:"print the SCP command that would be executed instead of executing it"`
+	PrivateNetwork string `cli-usage:"connect to the instance via its address on this managed Private Network (NAME|ID, requires --via)"`
+	Recursive      bool   `cli-short:"r" cli-usage:"recursively copy entire directories"`
+	ReplStr        string `cli-flag:"replace-str" cli-short:"i" cli-usage:"string to replace with the actual Compute instance information (i.e. username@IP-ADDRESS)"`
+	SCPOpts        string `cli-flag:"scp-options" cli-short:"o" cli-usage:"additional options to pass to the scp(1) command"`
+	Via            string `cli-usage:"bastion instance NAME|ID to connect to the instance through (ProxyJump)"`
+	Zone           string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceSCPCmd) buildSCPCommand() []string {
@@ -45,6 +48,10 @@ func (c *instanceSCPCmd) buildSCPCommand() []string {
 		cmd = append(cmd, "-r")
 	}
 
+	if c.scpInfo.proxyJump != "" {
+		cmd = append(cmd, "-J", c.scpInfo.proxyJump)
+	}
+
 	if c.SCPOpts != "" {
 		opts, err := shellquote.Split(c.SCPOpts)
 		if err == nil {
@@ -83,12 +90,29 @@ Example:
 
     exo compute instance scp my-instance hello-world.txt {}:
     exo compute instance scp -i%% my-instance %%:/etc/motd .
+
+To reach a private instance through a bastion instance, via its address on a
+managed Private Network:
+
+    exo compute instance scp --via my-bastion --private-network my-net my-instance {}:/etc/motd .
+
+The bastion instance is used as ssh(1) ProxyJump host: the connection to it is
+authenticated using the SSH agent or the SSH keys configured for it in the
+ssh_config(5) files.
 `
 }
 
 func (c *instanceSCPCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.PrivateNetwork != "" && c.Via == "" {
+		return fmt.Errorf("a bastion instance must be specified via --via when using --private-network")
+	}
+
+	return nil
 }
 
 func (c *instanceSCPCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -102,24 +126,27 @@ func (c *instanceSCPCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	if c.Login == "" {
-		instanceTemplate, err := cs.GetTemplate(ctx, c.Zone, *instance.TemplateID)
-		if err != nil {
-			return fmt.Errorf("error retrieving instance template: %w", err)
-		}
-		if instanceTemplate.DefaultUser != nil {
-			c.Login = *instanceTemplate.DefaultUser
-		}
+	cache := newResourceCache()
+
+	target, err := newInstanceSSHTarget(ctx, instance, c.Login, c.IPv6, c.PrivateNetwork, cache)
+	if err != nil {
+		return err
 	}
+	c.Login = target.user
+	c.scpInfo.ipAddress = target.host
+	c.scpInfo.keyFile = target.keyFile
 
-	c.scpInfo.keyFile = getInstanceSSHKeyPath(*instance.ID)
+	if c.Via != "" {
+		bastion, err := findBastionInstance([]string{c.Zone}, c.Via)
+		if err != nil {
+			return err
+		}
 
-	c.scpInfo.ipAddress = instance.PublicIPAddress.String()
-	if c.IPv6 {
-		if instance.IPv6Address == nil {
-			return fmt.Errorf("instance %q has no IPv6 address", c.Instance)
+		bastionTarget, err := newInstanceSSHTarget(ctx, bastion, "", c.IPv6, "", cache)
+		if err != nil {
+			return err
 		}
-		c.scpInfo.ipAddress = instance.IPv6Address.String()
+		c.scpInfo.proxyJump = bastionTarget.destination()
 	}
 
 	scpCmd := c.buildSCPCommand()
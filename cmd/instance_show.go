@@ -7,6 +7,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +29,7 @@ type instanceShowOutput struct {
 	SSHKey             string            `json:"ssh_key"`
 	DiskSize           string            `json:"disk_size"`
 	State              string            `json:"state"`
+	DeletionProtection bool              `json:"deletion_protection"`
 	Labels             map[string]string `json:"labels"`
 	ReverseDNS         string            `json:"reverse_dns" outputLabel:"Reverse DNS"`
 }
@@ -65,17 +67,17 @@ func (c *instanceShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *instanceShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
-	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+	if c.ShowUserData {
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
-	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
-	if err != nil {
-		if errors.Is(err, exoapi.ErrNotFound) {
-			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+		if err != nil {
+			if errors.Is(err, exoapi.ErrNotFound) {
+				return fmt.Errorf("resource not found in zone %q", c.Zone)
+			}
+			return err
 		}
-		return err
-	}
 
-	if c.ShowUserData {
 		if instance.UserData != nil {
 			userData, err := decodeUserData(*instance.UserData)
 			if err != nil {
@@ -88,6 +90,24 @@ func (c *instanceShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *instanceShowCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
+	if c.ShowUserData {
+		return nil, errors.New("showing user data is not supported with multiple accounts")
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, c.Zone))
+
+	instance, err := client.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return nil, fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return nil, err
+	}
+
 	out := instanceShowOutput{
 		AntiAffinityGroups: make([]string, 0),
 		CreationDate:       instance.CreatedAt.String(),
@@ -117,9 +137,9 @@ func (c *instanceShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 
 	if instance.AntiAffinityGroupIDs != nil {
 		for _, id := range *instance.AntiAffinityGroupIDs {
-			antiAffinityGroup, err := cs.GetAntiAffinityGroup(ctx, c.Zone, id)
+			antiAffinityGroup, err := client.GetAntiAffinityGroup(ctx, c.Zone, id)
 			if err != nil {
-				return fmt.Errorf("error retrieving Anti-Affinity Group: %w", err)
+				return nil, fmt.Errorf("error retrieving Anti-Affinity Group: %w", err)
 			}
 			out.AntiAffinityGroups = append(out.AntiAffinityGroups, *antiAffinityGroup.Name)
 		}
@@ -127,25 +147,25 @@ func (c *instanceShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 
 	if instance.ElasticIPIDs != nil {
 		for _, id := range *instance.ElasticIPIDs {
-			elasticIP, err := cs.GetElasticIP(ctx, c.Zone, id)
+			elasticIP, err := client.GetElasticIP(ctx, c.Zone, id)
 			if err != nil {
-				return fmt.Errorf("error retrieving Elastic IP: %w", err)
+				return nil, fmt.Errorf("error retrieving Elastic IP: %w", err)
 			}
 			out.ElasticIPs = append(out.ElasticIPs, elasticIP.IPAddress.String())
 		}
 	}
 
-	instanceType, err := cs.GetInstanceType(ctx, c.Zone, *instance.InstanceTypeID)
+	instanceType, err := client.GetInstanceType(ctx, c.Zone, *instance.InstanceTypeID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	out.InstanceType = fmt.Sprintf("%s.%s", *instanceType.Family, *instanceType.Size)
 
 	if instance.PrivateNetworkIDs != nil {
 		for _, id := range *instance.PrivateNetworkIDs {
-			privateNetwork, err := cs.GetPrivateNetwork(ctx, c.Zone, id)
+			privateNetwork, err := client.GetPrivateNetwork(ctx, c.Zone, id)
 			if err != nil {
-				return fmt.Errorf("error retrieving Private Network: %w", err)
+				return nil, fmt.Errorf("error retrieving Private Network: %w", err)
 			}
 			out.PrivateNetworks = append(out.PrivateNetworks, *privateNetwork.Name)
 		}
@@ -153,32 +173,36 @@ func (c *instanceShowCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 
 	if instance.SecurityGroupIDs != nil {
 		for _, id := range *instance.SecurityGroupIDs {
-			securityGroup, err := cs.GetSecurityGroup(ctx, c.Zone, id)
+			securityGroup, err := client.GetSecurityGroup(ctx, c.Zone, id)
 			if err != nil {
-				return fmt.Errorf("error retrieving Security Group: %w", err)
+				return nil, fmt.Errorf("error retrieving Security Group: %w", err)
 			}
 			out.SecurityGroups = append(out.SecurityGroups, *securityGroup.Name)
 		}
 	}
 
-	template, err := cs.GetTemplate(ctx, c.Zone, *instance.TemplateID)
+	template, err := client.GetTemplate(ctx, c.Zone, *instance.TemplateID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	out.Template = *template.Name
 
-	rdns, err := cs.GetInstanceReverseDNS(ctx, c.Zone, *instance.ID)
+	rdns, err := client.GetInstanceReverseDNS(ctx, c.Zone, *instance.ID)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
 			out.ReverseDNS = ""
 		} else {
-			return err
+			return nil, err
 		}
 	}
 
 	out.ReverseDNS = rdns
 
-	return c.outputFunc(&out, nil)
+	if out.DeletionProtection, err = getInstanceDeletionProtection(ctx, client, *instance.ID); err != nil {
+		return nil, fmt.Errorf("error retrieving deletion protection status: %w", err)
+	}
+
+	return &out, nil
 }
 
 func init() {
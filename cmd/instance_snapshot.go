@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -13,3 +19,53 @@ var instanceSnapshotCmd = &cobra.Command{
 func init() {
 	instanceCmd.AddCommand(instanceSnapshotCmd)
 }
+
+// promoteSnapshotToTemplate promotes a Compute instance snapshot to a private
+// template, and returns the resulting template.
+func promoteSnapshotToTemplate(
+	ctx context.Context,
+	zone string,
+	snapshotID string,
+	template oapi.PromoteSnapshotToTemplateJSONRequestBody,
+) (*exov2.Template, error) {
+	resp, err := cs.PromoteSnapshotToTemplateWithResponse(ctx, snapshotID, template)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := asyncOperationFromResponse(resp, resp.JSON200)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot promotion can take a _long time_.
+	if op, err = waitAsyncOperation(ctx, *op.Id, time.Hour); err != nil {
+		return nil, err
+	}
+
+	if op.State != nil && *op.State != oapi.OperationStateSuccess {
+		return nil, fmt.Errorf("operation %s %s", *op.Id, *op.State)
+	}
+
+	if op.Reference == nil || op.Reference.Id == nil {
+		return nil, fmt.Errorf("snapshot promotion operation doesn't reference any template")
+	}
+
+	return cs.GetTemplate(ctx, zone, *op.Reference.Id)
+}
+
+// snapshotSourceTemplate returns the template of the Compute instance the
+// specified snapshot has been created from.
+func snapshotSourceTemplate(ctx context.Context, zone string, snapshot *exov2.Snapshot) (*exov2.Template, error) {
+	instance, err := cs.GetInstance(ctx, zone, *snapshot.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Compute instance from snapshot: %w", err)
+	}
+
+	template, err := cs.GetTemplate(ctx, zone, *instance.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving Compute instance template from snapshot: %w", err)
+	}
+
+	return template, nil
+}
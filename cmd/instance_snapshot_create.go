@@ -7,6 +7,7 @@ import (
 
 	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +18,9 @@ type instanceSnapshotCreateCmd struct {
 
 	Instance string `cli-arg:"#" cli-usage:"INSTANCE-NAME|ID"`
 
-	Zone string `cli-short:"z" cli-usage:"instance zone"`
+	Async   bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
+	Promote string `cli-usage:"promote the snapshot to a private template NAME once created"`
+	Zone    string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceSnapshotCreateCmd) cmdAliases() []string { return gCreateAlias }
@@ -27,13 +30,25 @@ func (c *instanceSnapshotCreateCmd) cmdShort() string { return "Create a Compute
 func (c *instanceSnapshotCreateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command creates a Compute instance snapshot.
 
+Using the --promote flag, the snapshot is promoted to a private template once
+created (see "exo compute instance snapshot promote"), in which case the
+template details are output instead of the snapshot ones.
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instanceSnapshotShowOutput{}), ", "))
 }
 
 func (c *instanceSnapshotCreateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Async && c.Promote != "" {
+		cmdExitOnUsageError(cmd, "--async and --promote are mutually exclusive")
+	}
+
+	return nil
 }
 
 func (c *instanceSnapshotCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -48,6 +63,20 @@ func (c *instanceSnapshotCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if c.Async {
+		resp, err := cs.CreateSnapshotWithResponse(ctx, *instance.ID)
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+	}
+
 	var snapshot *egoscale.Snapshot
 	decorateAsyncOperation(fmt.Sprintf("Creating snapshot of instance %q...", c.Instance), func() {
 		snapshot, err = cs.CreateInstanceSnapshot(ctx, c.Zone, instance)
@@ -59,6 +88,37 @@ func (c *instanceSnapshotCreateCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if c.Promote != "" {
+		srcTemplate, err := cs.GetTemplate(ctx, c.Zone, *instance.TemplateID)
+		if err != nil {
+			return fmt.Errorf("error retrieving Compute instance template: %w", err)
+		}
+
+		var template *egoscale.Template
+		decorateAsyncOperation(fmt.Sprintf("Promoting snapshot %s to template %q...", *snapshot.ID, c.Promote), func() {
+			template, err = promoteSnapshotToTemplate(ctx, c.Zone, *snapshot.ID, oapi.PromoteSnapshotToTemplateJSONRequestBody{
+				Name:            c.Promote,
+				DefaultUser:     srcTemplate.DefaultUser,
+				PasswordEnabled: srcTemplate.PasswordEnabled,
+				SshKeyEnabled:   srcTemplate.SSHKeyEnabled,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("error promoting snapshot to template: %w", err)
+		}
+
+		if !gQuiet {
+			return (&instanceTemplateShowCmd{
+				cliCommandSettings: c.cliCommandSettings,
+				Template:           *template.ID,
+				Visibility:         "private",
+				Zone:               c.Zone,
+			}).cmdRun(nil, nil)
+		}
+
+		return nil
+	}
+
 	if !gQuiet {
 		return (&instanceSnapshotShowCmd{
 			cliCommandSettings: c.cliCommandSettings,
@@ -1,15 +1,29 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/exoscale/cli/utils"
 	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
 )
 
+// instanceSnapshotDownloadMaxAttempts represents the maximum number of
+// attempts made to download an exported snapshot before giving up.
+const instanceSnapshotDownloadMaxAttempts = 5
+
 type instanceSnapshotExportOutput struct {
 	URL      string `json:"url"`
 	Checksum string `json:"checksum"`
@@ -19,6 +33,21 @@ func (o *instanceSnapshotExportOutput) toJSON()  { outputJSON(o) }
 func (o *instanceSnapshotExportOutput) toText()  { outputText(o) }
 func (o *instanceSnapshotExportOutput) toTable() { outputTable(o) }
 
+// instanceSnapshotExportMetadata represents the metadata of a downloaded
+// snapshot export, written next to the image file.
+type instanceSnapshotExportMetadata struct {
+	SnapshotID   string `json:"snapshot_id"`
+	SnapshotName string `json:"snapshot_name"`
+	CreationDate string `json:"creation_date"`
+	InstanceID   string `json:"instance_id,omitempty"`
+	InstanceName string `json:"instance_name,omitempty"`
+	TemplateID   string `json:"template_id,omitempty"`
+	TemplateName string `json:"template_name,omitempty"`
+	Zone         string `json:"zone"`
+	Checksum     string `json:"checksum"`
+	Size         int64  `json:"size"`
+}
+
 type instanceSnapshotExportCmd struct {
 	cliCommandSettings `cli-cmd:"-"`
 
@@ -26,7 +55,8 @@ type instanceSnapshotExportCmd struct {
 
 	ID string `cli-arg:"#"`
 
-	Zone string `cli-short:"z" cli-usage:"snapshot zone"`
+	Download string `cli-short:"d" cli-usage:"download the exported snapshot to the specified PATH (file or directory)"`
+	Zone     string `cli-short:"z" cli-usage:"snapshot zone"`
 }
 
 func (c *instanceSnapshotExportCmd) cmdAliases() []string { return nil }
@@ -37,14 +67,45 @@ func (c *instanceSnapshotExportCmd) cmdShort() string {
 
 func (c *instanceSnapshotExportCmd) cmdLong() string {
 	return fmt.Sprintf(`This command exports a Compute instance snapshot.
-	
+
+Using the --download flag, the exported snapshot image is downloaded to the
+specified path and its checksum is verified. If the path is an existing
+directory, the image is saved in it as "<SNAPSHOT-ID>.qcow2". An interrupted
+download is resumed where it stopped, including when running the command
+again. A metadata file (source instance, template and snapshot creation date)
+is written next to the image, with a ".json" suffix.
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instanceSnapshotExportOutput{}), ", "))
 }
 
 func (c *instanceSnapshotExportCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	// Checking the download destination before exporting the snapshot, as
+	// exporting is a lengthy operation.
+	if c.Download != "" {
+		if _, err := os.Stat(c.downloadPath()); err == nil {
+			return fmt.Errorf("file %q already exists", c.downloadPath())
+		}
+	}
+
+	return nil
+}
+
+// downloadPath returns the local path to download the exported snapshot
+// image to: if the --download flag value is an existing directory, the image
+// is saved in it as "<SNAPSHOT-ID>.qcow2".
+func (c *instanceSnapshotExportCmd) downloadPath() string {
+	dst := filepath.Clean(c.Download)
+	if st, err := os.Stat(dst); err == nil && st.IsDir() {
+		dst = filepath.Join(dst, c.ID+".qcow2")
+	}
+
+	return dst
 }
 
 func (c *instanceSnapshotExportCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -66,6 +127,12 @@ func (c *instanceSnapshotExportCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	if c.Download != "" {
+		if err := c.download(ctx, snapshot, snapshotExport); err != nil {
+			return err
+		}
+	}
+
 	if !gQuiet {
 		return c.outputFunc(
 			&instanceSnapshotExportOutput{
@@ -78,6 +145,211 @@ func (c *instanceSnapshotExportCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// download downloads the exported snapshot image to the local path specified
+// by the user, verifies its checksum and writes its metadata file.
+func (c *instanceSnapshotExportCmd) download(
+	ctx context.Context,
+	snapshot *egoscale.Snapshot,
+	snapshotExport *egoscale.SnapshotExport,
+) error {
+	dst := c.downloadPath()
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("file %q already exists", dst)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	transport, err := newHTTPTransport(gCurrentAccount)
+	if err != nil {
+		return fmt.Errorf("unable to initialize HTTP transport: %w", err)
+	}
+
+	// The image is downloaded to a temporary ".part" file which is left in
+	// place if the download fails, so it can be resumed later on.
+	partPath := dst + ".part"
+	err = downloadFileWithResume(gContext, &http.Client{Transport: transport}, *snapshotExport.PresignedURL, partPath)
+	if err != nil {
+		return fmt.Errorf("unable to download snapshot: %w", err)
+	}
+
+	if err := checkExportedSnapshot(partPath, *snapshotExport.MD5sum); err != nil {
+		// There is no way to tell which part of the file is corrupted, so
+		// there is no point in keeping it around for a later resume.
+		_ = os.Remove(partPath)
+		return err
+	}
+
+	st, err := os.Stat(partPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(partPath, dst); err != nil {
+		return err
+	}
+
+	metadata := instanceSnapshotExportMetadata{
+		SnapshotID:   *snapshot.ID,
+		SnapshotName: *snapshot.Name,
+		CreationDate: snapshot.CreatedAt.String(),
+		InstanceID:   utils.DefaultString(snapshot.InstanceID, ""),
+		Zone:         c.Zone,
+		Checksum:     *snapshotExport.MD5sum,
+		Size:         st.Size(),
+	}
+
+	// The source instance might have been deleted since the snapshot
+	// creation, in which case its details are not available anymore.
+	if snapshot.InstanceID != nil {
+		if instance, err := cs.GetInstance(ctx, c.Zone, *snapshot.InstanceID); err == nil {
+			metadata.InstanceName = *instance.Name
+		}
+		if template, err := snapshotSourceTemplate(ctx, c.Zone, snapshot); err == nil {
+			metadata.TemplateID = *template.ID
+			metadata.TemplateName = *template.Name
+		}
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst+".json", append(data, '\n'), 0o600)
+}
+
+// downloadFileWithResume downloads the content located at url to the local
+// file path using the HTTP client specified, displaying a progress bar. If the file already
+// exists, the download is resumed from its current size using an HTTP range
+// request; transfers interrupted by network or server errors are retried the
+// same way.
+func downloadFileWithResume(ctx context.Context, client *http.Client, url, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	p := mpb.NewWithContext(ctx,
+		mpb.WithOutput(os.Stderr),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+	)
+
+	var bar *mpb.Bar
+	for attempt := 1; ; attempt++ {
+		retry, err := downloadFileRange(ctx, client, url, f, p, &bar)
+		if err == nil {
+			break
+		}
+
+		if !retry || ctx.Err() != nil || attempt == instanceSnapshotDownloadMaxAttempts {
+			if bar != nil {
+				bar.Abort(false)
+			}
+			p.Wait()
+			return err
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * 2 * time.Second):
+		case <-ctx.Done():
+		}
+	}
+	p.Wait()
+
+	return f.Close()
+}
+
+// downloadFileRange downloads the remaining content located at url, starting
+// from the current size of the local file f. Upon error, it also returns
+// whether the download is worth retrying, i.e. if the error is a network
+// error or a server-side (5xx) error.
+func downloadFileRange(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	f *os.File,
+	p *mpb.Progress,
+	bar **mpb.Bar,
+) (bool, error) {
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+
+	case http.StatusOK:
+		// The server doesn't support range requests (or there was nothing
+		// to resume from): start over from scratch.
+		if err := f.Truncate(0); err != nil {
+			return false, err
+		}
+		if offset, err = f.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The local file is already complete.
+		return false, nil
+
+	default:
+		return resp.StatusCode >= http.StatusInternalServerError,
+			fmt.Errorf("unexpected response from server: %s", resp.Status)
+	}
+
+	var total int64
+	if resp.ContentLength > 0 {
+		total = offset + resp.ContentLength
+	}
+
+	if *bar == nil {
+		*bar = p.AddBar(total,
+			mpb.PrependDecorators(
+				decor.Name("Downloading snapshot file... "),
+				decor.OnComplete(decor.CountersKibiByte("% .2f / % .2f"), "done"),
+			),
+			mpb.AppendDecorators(
+				decor.OnComplete(decor.EwmaETA(decor.ET_STYLE_GO, 90), ""),
+			),
+		)
+	} else {
+		(*bar).SetTotal(total, false)
+	}
+	(*bar).SetCurrent(offset)
+
+	n, err := io.Copy(f, (*bar).ProxyReader(resp.Body))
+	if err != nil {
+		// Errors writing to the local file are not worth retrying.
+		var pathErr *os.PathError
+		return !errors.As(err, &pathErr), err
+	}
+
+	if resp.ContentLength > 0 && n < resp.ContentLength {
+		return true, io.ErrUnexpectedEOF
+	}
+	(*bar).SetTotal(offset+n, true)
+
+	return false, nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(instanceSnapshotCmd, &instanceSnapshotExportCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_downloadFileWithResume(t *testing.T) {
+	content := bytes.Repeat([]byte("exoscale"), 1024)
+
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "image.qcow2", time.Time{}, bytes.NewReader(content))
+	}))
+	defer ts.Close()
+
+	gQuiet = true
+	defer func() { gQuiet = false }()
+
+	path := filepath.Join(t.TempDir(), "image.qcow2.part")
+	require.NoError(t, os.WriteFile(path, content[:1000], 0o600))
+
+	require.NoError(t, downloadFileWithResume(context.Background(), ts.Client(), ts.URL, path))
+	require.Equal(t, []string{"bytes=1000-"}, ranges)
+
+	actual, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, actual)
+
+	// Downloading an already complete file is a no-op.
+	require.NoError(t, downloadFileWithResume(context.Background(), ts.Client(), ts.URL, path))
+	actual, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, actual)
+}
+
+func Test_downloadFileWithResume_noRetryOnClientError(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	gQuiet = true
+	defer func() { gQuiet = false }()
+
+	path := filepath.Join(t.TempDir(), "image.qcow2.part")
+	require.Error(t, downloadFileWithResume(context.Background(), ts.Client(), ts.URL, path))
+	require.Equal(t, 1, requests)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+type instanceSnapshotPromoteCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"promote"`
+
+	ID string `cli-arg:"#" cli-usage:"SNAPSHOT-ID"`
+
+	Async           bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
+	DefaultUser     string `cli-usage:"template default username"`
+	Description     string `cli-usage:"template description"`
+	DisablePassword bool   `cli-usage:"disable password-based authentication"`
+	DisableSSHKey   bool   `cli-flag:"disable-ssh-key" cli-usage:"disable SSH key-based authentication"`
+	Name            string `cli-usage:"template name"`
+	Zone            string `cli-short:"z" cli-usage:"snapshot zone"`
+}
+
+func (c *instanceSnapshotPromoteCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSnapshotPromoteCmd) cmdShort() string {
+	return "Promote a Compute instance snapshot to a template"
+}
+
+func (c *instanceSnapshotPromoteCmd) cmdLong() string {
+	return fmt.Sprintf(`This command promotes a Compute instance snapshot to a private template,
+without having to export and register it.
+
+Unless specified using the corresponding flags, the template default user and
+authentication settings are inherited from the template of the Compute
+instance the snapshot has been created from.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceTemplateShowOutput{}), ", "))
+}
+
+func (c *instanceSnapshotPromoteCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Name == "" {
+		cmdExitOnUsageError(cmd, "no template name specified")
+	}
+
+	return nil
+}
+
+func (c *instanceSnapshotPromoteCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	snapshot, err := cs.GetSnapshot(ctx, c.Zone, c.ID)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	passwordEnabled := !c.DisablePassword
+	sshKeyEnabled := !c.DisableSSHKey
+
+	body := oapi.PromoteSnapshotToTemplateJSONRequestBody{
+		DefaultUser:     utils.NonEmptyStringPtr(c.DefaultUser),
+		Description:     utils.NonEmptyStringPtr(c.Description),
+		Name:            c.Name,
+		PasswordEnabled: &passwordEnabled,
+		SshKeyEnabled:   &sshKeyEnabled,
+	}
+
+	// The source instance might have been deleted since the snapshot creation,
+	// in which case there is nothing to inherit from.
+	srcTemplate, err := snapshotSourceTemplate(ctx, c.Zone, snapshot)
+	if err != nil && !errors.Is(err, exoapi.ErrNotFound) {
+		return err
+	}
+	if srcTemplate != nil {
+		if !cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DefaultUser)) {
+			body.DefaultUser = srcTemplate.DefaultUser
+		}
+		if !cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DisablePassword)) {
+			body.PasswordEnabled = srcTemplate.PasswordEnabled
+		}
+		if !cmd.Flags().Changed(mustCLICommandFlagName(c, &c.DisableSSHKey)) {
+			body.SshKeyEnabled = srcTemplate.SSHKeyEnabled
+		}
+	}
+
+	if c.Async {
+		resp, err := cs.PromoteSnapshotToTemplateWithResponse(ctx, *snapshot.ID, body)
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+	}
+
+	var template *egoscale.Template
+	decorateAsyncOperation(fmt.Sprintf("Promoting snapshot %s to template %q...", c.ID, c.Name), func() {
+		template, err = promoteSnapshotToTemplate(ctx, c.Zone, *snapshot.ID, body)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !gQuiet {
+		return (&instanceTemplateShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Template:           *template.ID,
+			Visibility:         "private",
+			Zone:               c.Zone,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceSnapshotCmd, &instanceSnapshotPromoteCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
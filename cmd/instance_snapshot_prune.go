@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+const (
+	instanceSnapshotPruneActionKeep   = "keep"
+	instanceSnapshotPruneActionDelete = "delete"
+)
+
+// instanceSnapshotRetentionPolicy represents a GFS (Grandfather-Father-Son)
+// retention policy for Compute instance snapshots: for each period type, the
+// most recent snapshot of the Keep* last periods having at least one snapshot
+// is retained.
+type instanceSnapshotRetentionPolicy struct {
+	KeepLast    int64
+	KeepDaily   int64
+	KeepWeekly  int64
+	KeepMonthly int64
+}
+
+func (p instanceSnapshotRetentionPolicy) isEmpty() bool {
+	return p.KeepLast <= 0 && p.KeepDaily <= 0 && p.KeepWeekly <= 0 && p.KeepMonthly <= 0
+}
+
+// apply evaluates the retention policy against the snapshots of a single
+// Compute instance, and returns the reasons for keeping each snapshot indexed
+// by snapshot ID. Snapshots absent from the returned map are to be deleted.
+func (p instanceSnapshotRetentionPolicy) apply(snapshots []*egoscale.Snapshot) map[string][]string {
+	sorted := make([]*egoscale.Snapshot, len(snapshots))
+	copy(sorted, snapshots)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].CreatedAt.After(*sorted[j].CreatedAt) })
+
+	rules := []struct {
+		name   string
+		keep   int64
+		period func(time.Time) string
+	}{
+		{"last", p.KeepLast, nil},
+		{"daily", p.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{"weekly", p.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{"monthly", p.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	kept := make(map[string][]string)
+	for _, rule := range rules {
+		var (
+			count      int64
+			lastPeriod string
+		)
+
+		for _, snapshot := range sorted {
+			if count >= rule.keep {
+				break
+			}
+
+			if rule.period != nil {
+				period := rule.period(snapshot.CreatedAt.UTC())
+				if period == lastPeriod {
+					continue
+				}
+				lastPeriod = period
+			}
+
+			kept[*snapshot.ID] = append(kept[*snapshot.ID], rule.name)
+			count++
+		}
+	}
+
+	return kept
+}
+
+type instanceSnapshotPruneItemOutput struct {
+	ID           string   `json:"id"`
+	Instance     string   `json:"instance"`
+	CreationDate string   `json:"creation_date"`
+	Action       string   `json:"action"`
+	Reasons      []string `json:"reasons"`
+}
+
+type instanceSnapshotPruneOutput []instanceSnapshotPruneItemOutput
+
+func (o *instanceSnapshotPruneOutput) toJSON()  { outputJSON(o) }
+func (o *instanceSnapshotPruneOutput) toText()  { outputText(o) }
+func (o *instanceSnapshotPruneOutput) toTable() { outputTable(o) }
+
+// deleted returns the IDs of the snapshots to be deleted.
+func (o instanceSnapshotPruneOutput) deleted() []string {
+	ids := make([]string, 0)
+	for _, item := range o {
+		if item.Action == instanceSnapshotPruneActionDelete {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+type instanceSnapshotPruneCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"prune"`
+
+	DryRun      bool   `cli-usage:"only print which snapshots would be deleted"`
+	Force       bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	KeepDaily   int64  `cli-usage:"number of daily snapshots to keep"`
+	KeepLast    int64  `cli-usage:"number of most recent snapshots to keep"`
+	KeepMonthly int64  `cli-usage:"number of monthly snapshots to keep"`
+	KeepWeekly  int64  `cli-usage:"number of weekly snapshots to keep"`
+	Label       string `cli-short:"l" cli-usage:"label selector of the Compute instances to prune snapshots of (e.g. backup=true)"`
+	Zone        string `cli-short:"z" cli-usage:"snapshots zone"`
+}
+
+func (c *instanceSnapshotPruneCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSnapshotPruneCmd) cmdShort() string {
+	return "Delete Compute instance snapshots according to a retention policy"
+}
+
+func (c *instanceSnapshotPruneCmd) cmdLong() string {
+	return fmt.Sprintf(`This command deletes the Compute instance snapshots of a zone not
+retained by a GFS (Grandfather-Father-Son) retention policy, evaluated
+separately for the snapshots of each Compute instance.
+
+For each of the --keep-daily, --keep-weekly and --keep-monthly flags, the
+most recent snapshot of each of the last N days, weeks or months having
+snapshots is kept. The --keep-last flag keeps the N most recent snapshots.
+A snapshot is kept as soon as any of the rules retains it. Snapshots that
+are not in a "ready" or "exported" state are never deleted.
+
+Using the --label flag, only the snapshots of Compute instances matching the
+label selector are considered.
+
+Example:
+
+    exo compute instance snapshot prune --keep-daily 7 --keep-weekly 4
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceSnapshotPruneItemOutput{}), ", "))
+}
+
+func (c *instanceSnapshotPruneCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.policy().isEmpty() {
+		cmdExitOnUsageError(cmd, "at least one of the --keep-* flags must be specified")
+	}
+
+	return nil
+}
+
+func (c *instanceSnapshotPruneCmd) policy() instanceSnapshotRetentionPolicy {
+	return instanceSnapshotRetentionPolicy{
+		KeepLast:    c.KeepLast,
+		KeepDaily:   c.KeepDaily,
+		KeepWeekly:  c.KeepWeekly,
+		KeepMonthly: c.KeepMonthly,
+	}
+}
+
+func (c *instanceSnapshotPruneCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	var (
+		selector labelSelector
+		err      error
+	)
+	if c.Label != "" {
+		if selector, err = parseLabelSelector(c.Label); err != nil {
+			return err
+		}
+	}
+
+	out, err := planInstanceSnapshotPrune(ctx, c.Zone, selector, c.policy())
+	if err != nil {
+		return err
+	}
+
+	deleted := out.deleted()
+	if !c.DryRun && len(deleted) > 0 {
+		if !c.Force {
+			if !askQuestion(fmt.Sprintf("Are you sure you want to delete %d snapshots?", len(deleted))) {
+				return nil
+			}
+		}
+
+		decorateAsyncOperation(fmt.Sprintf("Deleting %d snapshots...", len(deleted)), func() {
+			err = deleteInstanceSnapshots(ctx, c.Zone, deleted)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !gQuiet {
+		return c.outputFunc(&out, nil)
+	}
+
+	return nil
+}
+
+// planInstanceSnapshotPrune evaluates the retention policy against the
+// snapshots of the specified zone, optionally restricted to the snapshots of
+// the Compute instances matching the label selector.
+func planInstanceSnapshotPrune(
+	ctx context.Context,
+	zone string,
+	selector labelSelector,
+	policy instanceSnapshotRetentionPolicy,
+) (instanceSnapshotPruneOutput, error) {
+	snapshots, err := cs.ListSnapshots(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Compute instance snapshots in zone %s: %w", zone, err)
+	}
+
+	instances, err := cs.ListInstances(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Compute instances in zone %s: %w", zone, err)
+	}
+
+	instanceNames := make(map[string]string)
+	for _, instance := range instances {
+		if selector == nil || selector.matches(instanceLabels(instance)) {
+			instanceNames[*instance.ID] = *instance.Name
+		}
+	}
+
+	byInstance := make(map[string][]*egoscale.Snapshot)
+	for _, snapshot := range snapshots {
+		if selector != nil {
+			// Snapshots of deleted instances can't match any selector.
+			if _, ok := instanceNames[utils.DefaultString(snapshot.InstanceID, "")]; !ok {
+				continue
+			}
+		}
+
+		state := utils.DefaultString(snapshot.State, "")
+		if state != string(oapi.SnapshotStateReady) && state != string(oapi.SnapshotStateExported) {
+			continue
+		}
+
+		instanceID := utils.DefaultString(snapshot.InstanceID, "")
+		byInstance[instanceID] = append(byInstance[instanceID], snapshot)
+	}
+
+	out := make(instanceSnapshotPruneOutput, 0)
+	for instanceID, list := range byInstance {
+		instance, ok := instanceNames[instanceID]
+		if !ok {
+			instance = instanceID
+		}
+
+		kept := policy.apply(list)
+		for _, snapshot := range list {
+			item := instanceSnapshotPruneItemOutput{
+				ID:           *snapshot.ID,
+				Instance:     instance,
+				CreationDate: snapshot.CreatedAt.String(),
+				Action:       instanceSnapshotPruneActionDelete,
+				Reasons:      make([]string, 0),
+			}
+
+			if reasons, ok := kept[*snapshot.ID]; ok {
+				item.Action = instanceSnapshotPruneActionKeep
+				item.Reasons = reasons
+			}
+
+			out = append(out, item)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Instance != out[j].Instance {
+			return out[i].Instance < out[j].Instance
+		}
+		return out[i].CreationDate > out[j].CreationDate
+	})
+
+	return out, nil
+}
+
+// instanceSnapshotDeleteError represents the failure to delete a Compute
+// instance snapshot.
+type instanceSnapshotDeleteError struct {
+	id  string
+	err error
+}
+
+func (e *instanceSnapshotDeleteError) Error() string {
+	return fmt.Sprintf("unable to delete snapshot %s: %s", e.id, e.err)
+}
+
+func (e *instanceSnapshotDeleteError) Unwrap() error { return e.err }
+
+// deleteInstanceSnapshots deletes the specified Compute instance snapshots in
+// parallel (bounded by the "--parallelism" global flag). It returns a
+// multierror.Error containing an instanceSnapshotDeleteError for each
+// snapshot that couldn't be deleted.
+func deleteInstanceSnapshots(ctx context.Context, zone string, ids []string) error {
+	return forEachParallel(len(ids), func(i int) error {
+		if err := cs.DeleteSnapshot(ctx, zone, &egoscale.Snapshot{ID: &ids[i]}); err != nil {
+			return &instanceSnapshotDeleteError{id: ids[i], err: err}
+		}
+
+		return nil
+	})
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceSnapshotCmd, &instanceSnapshotPruneCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instanceSnapshotRetentionPolicy_apply(t *testing.T) {
+	snapshot := func(id, date string) *egoscale.Snapshot {
+		createdAt, err := time.Parse(time.RFC3339, date)
+		require.NoError(t, err)
+		return &egoscale.Snapshot{ID: &id, CreatedAt: &createdAt}
+	}
+
+	snapshots := []*egoscale.Snapshot{
+		snapshot("a", "2021-06-01T02:00:00Z"), // Tuesday, week 22
+		snapshot("b", "2021-06-07T02:00:00Z"), // Monday, week 23
+		snapshot("c", "2021-06-13T02:00:00Z"), // Sunday, week 23
+		snapshot("d", "2021-06-14T02:00:00Z"), // Monday, week 24
+		snapshot("e", "2021-06-15T02:00:00Z"),
+		snapshot("f", "2021-06-15T14:00:00Z"),
+	}
+
+	tests := []struct {
+		name     string
+		policy   instanceSnapshotRetentionPolicy
+		expected map[string][]string
+	}{
+		{
+			name:     "last",
+			policy:   instanceSnapshotRetentionPolicy{KeepLast: 2},
+			expected: map[string][]string{"f": {"last"}, "e": {"last"}},
+		},
+		{
+			name:     "daily",
+			policy:   instanceSnapshotRetentionPolicy{KeepDaily: 3},
+			expected: map[string][]string{"f": {"daily"}, "d": {"daily"}, "c": {"daily"}},
+		},
+		{
+			name:   "daily and weekly",
+			policy: instanceSnapshotRetentionPolicy{KeepDaily: 1, KeepWeekly: 3},
+			expected: map[string][]string{
+				"f": {"daily", "weekly"},
+				"c": {"weekly"},
+				"a": {"weekly"},
+			},
+		},
+		{
+			name:     "monthly",
+			policy:   instanceSnapshotRetentionPolicy{KeepMonthly: 12},
+			expected: map[string][]string{"f": {"monthly"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.policy.apply(snapshots))
+		})
+	}
+}
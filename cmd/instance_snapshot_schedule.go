@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	egoscale "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+)
+
+type instanceSnapshotScheduleReportItem struct {
+	ID       string `json:"id"`
+	Instance string `json:"instance"`
+}
+
+// instanceSnapshotScheduleReport represents the result of a scheduled backup
+// run, logged as a JSON object.
+type instanceSnapshotScheduleReport struct {
+	Time    time.Time                            `json:"time"`
+	Zone    string                               `json:"zone"`
+	Created []instanceSnapshotScheduleReportItem `json:"created"`
+	Deleted []instanceSnapshotScheduleReportItem `json:"deleted"`
+	Errors  []string                             `json:"errors,omitempty"`
+}
+
+type instanceSnapshotScheduleCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"schedule"`
+
+	Every       string `cli-usage:"interval between two runs (e.g. 24h), run once and exit if not specified"`
+	KeepDaily   int64  `cli-usage:"number of daily snapshots to keep"`
+	KeepLast    int64  `cli-usage:"number of most recent snapshots to keep"`
+	KeepMonthly int64  `cli-usage:"number of monthly snapshots to keep"`
+	KeepWeekly  int64  `cli-usage:"number of weekly snapshots to keep"`
+	Label       string `cli-short:"l" cli-usage:"label selector of the Compute instances to snapshot (e.g. backup=true)"`
+	Zone        string `cli-short:"z" cli-usage:"Compute instances zone"`
+}
+
+func (c *instanceSnapshotScheduleCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSnapshotScheduleCmd) cmdShort() string {
+	return "Snapshot Compute instances periodically"
+}
+
+func (c *instanceSnapshotScheduleCmd) cmdLong() string {
+	return `This command creates a snapshot of every Compute instance matching a label
+selector, then deletes the snapshots of these instances not retained by the
+retention policy specified using the --keep-* flags (see "exo compute
+instance snapshot prune"). If no --keep-* flag is specified, no snapshots
+are deleted.
+
+Using the --every flag, the command runs in the foreground until interrupted,
+repeating the operations at the specified interval. Otherwise it runs once and
+exits with an error status if any operation failed, which is suitable for
+periodic execution by cron or a systemd timer.
+
+The result of every run (snapshots created and deleted, errors) is logged on
+the standard output as a JSON object.
+
+Example:
+
+    exo compute instance snapshot schedule --label backup=true --every 24h \
+        --keep-daily 7 --keep-weekly 4`
+}
+
+func (c *instanceSnapshotScheduleCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.Label == "" {
+		cmdExitOnUsageError(cmd, "no label selector specified")
+	}
+
+	if c.Every != "" {
+		if interval, err := time.ParseDuration(c.Every); err != nil || interval <= 0 {
+			cmdExitOnUsageError(cmd, fmt.Sprintf("invalid --every value %q", c.Every))
+		}
+	}
+
+	return nil
+}
+
+func (c *instanceSnapshotScheduleCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	// Snapshot creation can take a _long time_, raising
+	// the Exoscale API client timeout as a precaution.
+	cs.Client.SetTimeout(time.Hour)
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	selector, err := parseLabelSelector(c.Label)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+
+	if c.Every == "" {
+		report := c.run(ctx, selector)
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("%d errors occurred during the run", len(report.Errors))
+		}
+		return nil
+	}
+
+	interval, err := time.ParseDuration(c.Every)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := enc.Encode(c.run(ctx, selector)); err != nil {
+			return err
+		}
+
+		select {
+		case <-gContext.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// run creates a snapshot of the Compute instances matching the label
+// selector, then prunes their snapshots according to the retention policy.
+func (c *instanceSnapshotScheduleCmd) run(ctx context.Context, selector labelSelector) *instanceSnapshotScheduleReport {
+	report := &instanceSnapshotScheduleReport{
+		Time:    time.Now().UTC(),
+		Zone:    c.Zone,
+		Created: make([]instanceSnapshotScheduleReportItem, 0),
+		Deleted: make([]instanceSnapshotScheduleReportItem, 0),
+	}
+
+	instances, err := cs.ListInstances(ctx, c.Zone)
+	if err != nil {
+		report.Errors = append(report.Errors,
+			fmt.Sprintf("unable to list Compute instances in zone %s: %s", c.Zone, err))
+		return report
+	}
+
+	targets := make([]*egoscale.Instance, 0)
+	for _, instance := range instances {
+		if selector.matches(instanceLabels(instance)) {
+			targets = append(targets, instance)
+		}
+	}
+
+	var mu sync.Mutex
+	err = forEachParallel(len(targets), func(i int) error {
+		instance := targets[i]
+
+		snapshot, err := cs.CreateInstanceSnapshot(ctx, c.Zone, instance)
+		if err != nil {
+			return fmt.Errorf("unable to create snapshot of instance %s: %w", *instance.Name, err)
+		}
+
+		mu.Lock()
+		report.Created = append(report.Created, instanceSnapshotScheduleReportItem{
+			ID:       *snapshot.ID,
+			Instance: *instance.Name,
+		})
+		mu.Unlock()
+
+		return nil
+	})
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		for _, e := range merr.Errors {
+			report.Errors = append(report.Errors, e.Error())
+		}
+	}
+	sort.Slice(report.Created, func(i, j int) bool { return report.Created[i].Instance < report.Created[j].Instance })
+
+	policy := instanceSnapshotRetentionPolicy{
+		KeepLast:    c.KeepLast,
+		KeepDaily:   c.KeepDaily,
+		KeepWeekly:  c.KeepWeekly,
+		KeepMonthly: c.KeepMonthly,
+	}
+	if policy.isEmpty() {
+		return report
+	}
+
+	plan, err := planInstanceSnapshotPrune(ctx, c.Zone, selector, policy)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+
+	failed := make(map[string]bool)
+	if errors.As(deleteInstanceSnapshots(ctx, c.Zone, plan.deleted()), &merr) {
+		for _, e := range merr.Errors {
+			var deleteErr *instanceSnapshotDeleteError
+			if errors.As(e, &deleteErr) {
+				failed[deleteErr.id] = true
+			}
+			report.Errors = append(report.Errors, e.Error())
+		}
+	}
+
+	for _, item := range plan {
+		if item.Action == instanceSnapshotPruneActionDelete && !failed[item.ID] {
+			report.Deleted = append(report.Deleted, instanceSnapshotScheduleReportItem{
+				ID:       item.ID,
+				Instance: item.Instance,
+			})
+		}
+	}
+
+	return report
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceSnapshotCmd, &instanceSnapshotScheduleCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -19,17 +19,20 @@ type instanceSSHCmd struct {
 	sshInfo struct {
 		ipAddress string
 		keyFile   string
+		proxyJump string
 	} `cli-cmd:"-"`
 	_ bool `cli-cmd:"ssh"`
 
 	Instance string `cli-arg:"#" cli-usage:"INSTANCE-NAME|ID"`
 
-	IPv6        bool   `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
-	Login       string `cli-short:"l" cli-help:"SSH username to use for logging in (default: instance template default username)"`
-	PrintCmd    bool   `cli-flag:"print-command" cli-usage:"print the SSH command that would be executed instead of executing it"`
-	PrintConfig bool   `cli-flag:"print-ssh-config" cli-usage:"print the corresponding SSH information in a format compatible with ssh_config(5)"`
-	SSHOpts     string `cli-flag:"ssh-options" cli-short:"o" cli-usage:"additional options to pass to the ssh(1) command"`
-	Zone        string `cli-short:"z" cli-usage:"instance zone"`
+	IPv6           bool   `cli-flag:"ipv6" cli-short:"6" cli-help:"connect to the instance via its IPv6 address"`
+	Login          string `cli-short:"l" cli-help:"SSH username to use for logging in (default: instance template default username)"`
+	PrintCmd       bool   `cli-flag:"print-command" cli-usage:"print the SSH command that would be executed instead of executing it"`
+	PrintConfig    bool   `cli-flag:"print-ssh-config" cli-usage:"print the corresponding SSH information in a format compatible with ssh_config(5)"`
+	PrivateNetwork string `cli-usage:"connect to the instance via its address on this managed Private Network (NAME|ID, requires --via)"`
+	SSHOpts        string `cli-flag:"ssh-options" cli-short:"o" cli-usage:"additional options to pass to the ssh(1) command"`
+	Via            string `cli-usage:"bastion instance NAME|ID to connect to the instance through (ProxyJump)"`
+	Zone           string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceSSHCmd) buildSSHCommand() []string {
@@ -47,6 +50,10 @@ func (c *instanceSSHCmd) buildSSHCommand() []string {
 		cmd = append(cmd, "-l", c.Login)
 	}
 
+	if c.sshInfo.proxyJump != "" {
+		cmd = append(cmd, "-J", c.sshInfo.proxyJump)
+	}
+
 	if c.SSHOpts != "" {
 		opts, err := shellquote.Split(c.SSHOpts)
 		if err == nil {
@@ -69,12 +76,29 @@ func (c *instanceSSHCmd) cmdLong() string {
 To pass custom SSH options:
 
     exo compute instance ssh -o "-p 2222 -A" my-instance
+
+To connect to a private instance through a bastion instance, via its address
+on a managed Private Network:
+
+    exo compute instance ssh --via my-bastion --private-network my-net my-instance
+
+The bastion instance is used as ssh(1) ProxyJump host: the connection to it is
+authenticated using the SSH agent or the SSH keys configured for it in the
+ssh_config(5) files.
 `
 }
 
 func (c *instanceSSHCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.PrivateNetwork != "" && c.Via == "" {
+		return fmt.Errorf("a bastion instance must be specified via --via when using --private-network")
+	}
+
+	return nil
 }
 
 func (c *instanceSSHCmd) cmdRun(_ *cobra.Command, _ []string) error {
@@ -88,24 +112,27 @@ func (c *instanceSSHCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	if c.Login == "" {
-		instanceTemplate, err := cs.GetTemplate(ctx, c.Zone, *instance.TemplateID)
-		if err != nil {
-			return fmt.Errorf("error retrieving instance template: %w", err)
-		}
-		if instanceTemplate.DefaultUser != nil {
-			c.Login = *instanceTemplate.DefaultUser
-		}
+	cache := newResourceCache()
+
+	target, err := newInstanceSSHTarget(ctx, instance, c.Login, c.IPv6, c.PrivateNetwork, cache)
+	if err != nil {
+		return err
 	}
+	c.Login = target.user
+	c.sshInfo.ipAddress = target.host
+	c.sshInfo.keyFile = target.keyFile
 
-	c.sshInfo.keyFile = getInstanceSSHKeyPath(*instance.ID)
+	if c.Via != "" {
+		bastion, err := findBastionInstance([]string{c.Zone}, c.Via)
+		if err != nil {
+			return err
+		}
 
-	c.sshInfo.ipAddress = instance.PublicIPAddress.String()
-	if c.IPv6 {
-		if instance.IPv6Address == nil {
-			return fmt.Errorf("instance %q has no IPv6 address", c.Instance)
+		bastionTarget, err := newInstanceSSHTarget(ctx, bastion, "", c.IPv6, "", cache)
+		if err != nil {
+			return err
 		}
-		c.sshInfo.ipAddress = instance.IPv6Address.String()
+		c.sshInfo.proxyJump = bastionTarget.destination()
 	}
 
 	sshCmd := c.buildSSHCommand()
@@ -123,6 +150,10 @@ func (c *instanceSSHCmd) cmdRun(_ *cobra.Command, _ []string) error {
 			_, _ = fmt.Fprintf(out, "IdentityFile %q\n", c.sshInfo.keyFile)
 		}
 
+		if c.sshInfo.proxyJump != "" {
+			_, _ = fmt.Fprintf(out, "ProxyJump %s\n", c.sshInfo.proxyJump)
+		}
+
 		fmt.Print(out.String())
 		return nil
 
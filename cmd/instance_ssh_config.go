@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/spf13/cobra"
+)
+
+type instanceSSHConfigCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"ssh-config"`
+
+	File           string `cli-short:"f" cli-usage:"file to write the SSH configuration to (default: standard output)"`
+	IPv6           bool   `cli-flag:"ipv6" cli-short:"6" cli-usage:"connect to the instances via their IPv6 address"`
+	PrivateNetwork string `cli-usage:"connect to the instances via their address on this managed Private Network (NAME|ID, requires --via)"`
+	Selector       string `cli-short:"l" cli-usage:"label selector matching the instances to generate the SSH configuration for"`
+	Via            string `cli-usage:"bastion instance NAME|ID to connect to the instances through (ProxyJump)"`
+	Zone           string `cli-short:"z" cli-usage:"instances zone (default: all zones)"`
+}
+
+func (c *instanceSSHConfigCmd) cmdAliases() []string { return nil }
+
+func (c *instanceSSHConfigCmd) cmdShort() string {
+	return "Generate an SSH configuration for Compute instances"
+}
+
+func (c *instanceSSHConfigCmd) cmdLong() string {
+	return `This command generates an ssh_config(5) file containing a host entry for
+each Compute instance (optionally filtered by zone or label selector, see "exo
+compute instance list --help" for the selector syntax), allowing to connect to
+instances by name using the ssh(1) command. Instances sharing the same name in
+different zones are aliased NAME.ZONE.
+
+Instances without public IP address (e.g. created with --private-instance) can
+be reached through a bastion instance using the --via flag, combined with the
+--private-network flag to connect to the instances via their address on a
+managed Private Network.
+
+The generated file is intended to be included from the user SSH configuration:
+
+    exo compute instance ssh-config -f ~/.ssh/exoscale.conf
+    echo "Include ~/.ssh/exoscale.conf" >> ~/.ssh/config
+    ssh web-1
+`
+}
+
+func (c *instanceSSHConfigCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.PrivateNetwork != "" && c.Via == "" {
+		return fmt.Errorf("a bastion instance must be specified via --via when using --private-network")
+	}
+
+	return nil
+}
+
+func (c *instanceSSHConfigCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	zones := []string{c.Zone}
+	if c.Zone == "" {
+		zones = listZoneNames(cs.Client, gCurrentAccount)
+	}
+
+	instances, err := findInstances(zones, nil, c.Selector)
+	if err != nil {
+		return err
+	}
+
+	var bastion *exov2.Instance
+	if c.Via != "" {
+		if bastion, err = findBastionInstance(zones, c.Via); err != nil {
+			return err
+		}
+
+		found := false
+		for _, instance := range instances {
+			if *instance.ID == *bastion.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			instances = append(instances, bastion)
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if *instances[i].Name == *instances[j].Name {
+			return *instances[i].Zone < *instances[j].Zone
+		}
+		return *instances[i].Name < *instances[j].Name
+	})
+
+	names := make(map[string]int)
+	for _, instance := range instances {
+		names[*instance.Name]++
+	}
+	hostAlias := func(instance *exov2.Instance) string {
+		if names[*instance.Name] > 1 {
+			return *instance.Name + "." + *instance.Zone
+		}
+		return *instance.Name
+	}
+
+	var (
+		cache = newResourceCache()
+		out   = bytes.NewBuffer(nil)
+	)
+
+	_, _ = fmt.Fprintln(out, "# Generated by exo compute instance ssh-config, do not edit.")
+
+	for _, instance := range instances {
+		// The bastion instance is always reached via its public address.
+		privateNetwork := c.PrivateNetwork
+		if bastion != nil && *instance.ID == *bastion.ID {
+			privateNetwork = ""
+		}
+
+		target, err := newInstanceSSHTarget(gContext, instance, "", c.IPv6, privateNetwork, cache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping instance %q: %v\n", *instance.Name, err)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(out, "\nHost %s\n", hostAlias(instance))
+		_, _ = fmt.Fprintf(out, "  HostName %s\n", target.host)
+
+		if target.user != "" {
+			_, _ = fmt.Fprintf(out, "  User %s\n", target.user)
+		}
+
+		if _, err := os.Stat(target.keyFile); err == nil {
+			_, _ = fmt.Fprintf(out, "  IdentityFile %q\n", target.keyFile)
+		}
+
+		if bastion != nil && *instance.ID != *bastion.ID {
+			_, _ = fmt.Fprintf(out, "  ProxyJump %s\n", hostAlias(bastion))
+		}
+	}
+
+	if c.File == "" {
+		fmt.Print(out.String())
+		return nil
+	}
+
+	if err := os.WriteFile(c.File, out.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("unable to write SSH configuration file: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceSSHConfigCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
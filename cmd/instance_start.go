@@ -14,10 +14,11 @@ type instanceStartCmd struct {
 
 	_ bool `cli-cmd:"start"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
 
 	Force         bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
 	RescueProfile string `cli-usage:"rescue profile to start the instance with"`
+	Selector      string `cli-short:"l" cli-usage:"label selector of the instances to start (e.g. env=prod,role!=db)"`
 	Zone          string `cli-short:"z" cli-usage:"instance zone"`
 }
 
@@ -25,16 +26,52 @@ func (c *instanceStartCmd) cmdAliases() []string { return nil }
 
 func (c *instanceStartCmd) cmdShort() string { return "Start a Compute instance" }
 
-func (c *instanceStartCmd) cmdLong() string { return "" }
+func (c *instanceStartCmd) cmdLong() string {
+	return fmt.Sprintf(`This command starts a Compute instance, or all the Compute instances of
+the zone matching the label selector specified using the --selector flag.
+
+%s
+
+Example:
+
+    exo compute instance start --selector env=staging,role!=db
+`,
+		labelSelectorHelp)
+}
 
 func (c *instanceStartCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+	return validateInstanceOrSelector(c.Instance, c.Selector)
 }
 
 func (c *instanceStartCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	opts := make([]egoscale.StartInstanceOpt, 0)
+	if c.RescueProfile != "" {
+		opts = append(opts, egoscale.StartInstanceWithRescueProfile(c.RescueProfile))
+	}
+
+	if c.Selector != "" {
+		instances, err := findInstancesBySelector(ctx, c.Zone, c.Selector)
+		if err != nil {
+			return err
+		}
+
+		if !c.Force {
+			if !askBulkInstanceQuestion("start", instances) {
+				return nil
+			}
+		}
+
+		return bulkInstanceOperation("Starting instance", instances, func(instance *egoscale.Instance) error {
+			return cs.StartInstance(ctx, c.Zone, instance, opts...)
+		})
+	}
+
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
@@ -49,11 +86,6 @@ func (c *instanceStartCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	opts := make([]egoscale.StartInstanceOpt, 0)
-	if c.RescueProfile != "" {
-		opts = append(opts, egoscale.StartInstanceWithRescueProfile(c.RescueProfile))
-	}
-
 	decorateAsyncOperation(fmt.Sprintf("Starting instance %q...", c.Instance), func() {
 		err = cs.StartInstance(ctx, c.Zone, instance, opts...)
 	})
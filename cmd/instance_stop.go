@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -13,26 +14,58 @@ type instanceStopCmd struct {
 
 	_ bool `cli-cmd:"stop"`
 
-	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+	Instance string `cli-arg:"?" cli-usage:"NAME|ID"`
 
-	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
-	Zone  string `cli-short:"z" cli-usage:"instance zone"`
+	Force    bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
+	Selector string `cli-short:"l" cli-usage:"label selector of the instances to stop (e.g. env=prod,role!=db)"`
+	Zone     string `cli-short:"z" cli-usage:"instance zone"`
 }
 
 func (c *instanceStopCmd) cmdAliases() []string { return nil }
 
 func (c *instanceStopCmd) cmdShort() string { return "Stop a Compute instance" }
 
-func (c *instanceStopCmd) cmdLong() string { return "" }
+func (c *instanceStopCmd) cmdLong() string {
+	return fmt.Sprintf(`This command stops a Compute instance, or all the Compute instances of
+the zone matching the label selector specified using the --selector flag.
+
+%s
+
+Example:
+
+    exo compute instance stop --selector env=staging,role!=db
+`,
+		labelSelectorHelp)
+}
 
 func (c *instanceStopCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+	return validateInstanceOrSelector(c.Instance, c.Selector)
 }
 
 func (c *instanceStopCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
 
+	if c.Selector != "" {
+		instances, err := findInstancesBySelector(ctx, c.Zone, c.Selector)
+		if err != nil {
+			return err
+		}
+
+		if !c.Force {
+			if !askBulkInstanceQuestion("stop", instances) {
+				return nil
+			}
+		}
+
+		return bulkInstanceOperation("Stopping instance", instances, func(instance *egoscale.Instance) error {
+			return cs.StopInstance(ctx, c.Zone, instance)
+		})
+	}
+
 	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
 	if err != nil {
 		if errors.Is(err, exoapi.ErrNotFound) {
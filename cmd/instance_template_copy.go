@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
+)
+
+type instanceTemplateCopyItemOutput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Zone string `json:"zone"`
+}
+
+type instanceTemplateCopyOutput []instanceTemplateCopyItemOutput
+
+func (o *instanceTemplateCopyOutput) toJSON()  { outputJSON(o) }
+func (o *instanceTemplateCopyOutput) toText()  { outputText(o) }
+func (o *instanceTemplateCopyOutput) toTable() { outputTable(o) }
+
+type instanceTemplateCopyCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"copy"`
+
+	Template string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	ToZones    []string `cli-flag:"to-zone" cli-usage:"zone to copy the template to (can be specified multiple times)"`
+	Visibility string   `cli-short:"v" cli-usage:"template visibility (public|private)"`
+	Zone       string   `cli-short:"z" cli-usage:"template zone"`
+}
+
+func (c *instanceTemplateCopyCmd) cmdAliases() []string { return nil }
+
+func (c *instanceTemplateCopyCmd) cmdShort() string {
+	return "Copy a Compute instance template to other zones"
+}
+
+func (c *instanceTemplateCopyCmd) cmdLong() string {
+	return fmt.Sprintf(`This command copies a Compute instance template to other zones, concurrently.
+
+Example:
+
+    exo compute instance-template copy my-template --to-zone de-fra-1,at-vie-1
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceTemplateCopyItemOutput{}), ", "))
+}
+
+func (c *instanceTemplateCopyCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if len(c.ToZones) == 0 {
+		cmdExitOnUsageError(cmd, "no destination zones specified")
+	}
+
+	for _, zone := range c.ToZones {
+		if zone == c.Zone {
+			cmdExitOnUsageError(cmd, fmt.Sprintf("destination zone %q is the template zone", zone))
+		}
+	}
+
+	return nil
+}
+
+func (c *instanceTemplateCopyCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	template, err := cs.FindTemplate(ctx, c.Zone, c.Template, c.Visibility)
+	if err != nil {
+		return fmt.Errorf(
+			"no template %q found with visibility %s in zone %s",
+			c.Template,
+			c.Visibility,
+			c.Zone,
+		)
+	}
+
+	var (
+		out = make(instanceTemplateCopyOutput, 0)
+		mu  sync.Mutex
+	)
+
+	p := mpb.NewWithContext(gContext,
+		mpb.WithOutput(os.Stderr),
+		mpb.ContainerOptOn(mpb.WithOutput(nil), func() bool { return gQuiet }),
+	)
+
+	bars := make(map[string]*mpb.Bar)
+	for _, zone := range c.ToZones {
+		name := fmt.Sprintf("Copying template %q to zone %s...", *template.Name, zone)
+		bars[zone] = p.AddSpinner(
+			1,
+			mpb.SpinnerOnLeft,
+			mpb.PrependDecorators(decor.Name(name, decor.WC{W: len(name) + 1, C: decor.DidentRight})),
+			mpb.AppendDecorators(decor.OnComplete(decor.Elapsed(decor.ET_STYLE_GO), "done")),
+		)
+	}
+
+	err = forEachZone(c.ToZones, func(zone string) error {
+		copied, err := cs.CopyTemplate(ctx, c.Zone, template, zone)
+		if err != nil {
+			bars[zone].Abort(false)
+			return fmt.Errorf("zone %s: %w", zone, err)
+		}
+		bars[zone].Increment(1)
+
+		mu.Lock()
+		out = append(out, instanceTemplateCopyItemOutput{
+			ID:   *copied.ID,
+			Name: *copied.Name,
+			Zone: zone,
+		})
+		mu.Unlock()
+
+		return nil
+	})
+	p.Wait()
+
+	if len(out) > 0 && !gQuiet {
+		sort.Slice(out, func(i, j int) bool { return out[i].Zone < out[j].Zone })
+		if err := c.outputFunc(&out, nil); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceTemplateCmd, &instanceTemplateCopyCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+
+		Visibility: "private",
+	}))
+}
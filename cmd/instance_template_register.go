@@ -1,13 +1,23 @@
 package cmd
 
 import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/exoscale/cli/utils"
 	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -20,13 +30,16 @@ type instanceTemplateRegisterCmd struct {
 	URL      string `cli-arg:"#"`
 	Checksum string `cli-arg:"#"`
 
+	Async           bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
 	BootMode        string `cli-usage:"template boot mode (legacy|uefi)"`
+	Bucket          string `cli-usage:"existing SOS bucket to upload the --from-file image to (default: temporary bucket)"`
 	Description     string `cli-usage:"template description"`
 	Build           string `cli-usage:"template build"`
 	Version         string `cli-usage:"template version"`
 	Maintainer      string `cli-usage:"template maintainer"`
 	DisablePassword bool   `cli-usage:"disable password-based authentication"`
 	DisableSSHKey   bool   `cli-flag:"disable-ssh-key" cli-usage:"disable SSH key-based authentication"`
+	FromFile        string `cli-usage:"path to a local disk image file to register as template"`
 	FromSnapshot    string `cli-usage:"ID of a Compute instance snapshot to register as template"`
 	Timeout         int64  `cli-usage:"registration timeout duration in seconds"`
 	Username        string `cli-usage:"template default username"`
@@ -42,6 +55,19 @@ func (c *instanceTemplateRegisterCmd) cmdShort() string {
 func (c *instanceTemplateRegisterCmd) cmdLong() string {
 	return fmt.Sprintf(`This command registers a new Compute instance template.
 
+Note: to create a template from a Compute instance snapshot without having to
+export it first, use the "exo compute instance snapshot promote" command.
+
+Using the --from-file flag, the URL and checksum arguments are replaced by a
+local disk image file (e.g. QCOW2): the image checksum is computed, then the
+image is uploaded to a temporary SOS bucket (or an existing one specified
+using the --bucket flag) in the template zone and registered from there. The
+uploaded image is deleted once the registration has completed.
+
+Example:
+
+    exo compute instance-template register my-template --from-file image.qcow2
+
 Supported output template annotations: %s`,
 		strings.Join(outputterTemplateAnnotations(&instanceTemplateShowOutput{}), ", "))
 }
@@ -49,21 +75,44 @@ Supported output template annotations: %s`,
 func (c *instanceTemplateRegisterCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 	cmdSetZoneFlagFromDefault(cmd)
 
-	// In case the user specified a snapshot ID using the `--from-snapshot` flag,
-	// we add empty positional argument placeholders in order to trick the
+	// In case the user specified a snapshot ID using the `--from-snapshot` flag
+	// (or a local image file using the `--from-file` flag), we add empty
+	// positional argument placeholders in order to trick the
 	// cliCommandDefaultPreRun() wrapper into believing URL/Checksum args were provided,
 	// but the actual command function won't use them since it will dynamically retrieve
-	// this information from the specified snapshot export information.
+	// this information from the specified snapshot export information (or image file).
 
 	snapshotID, err := cmd.Flags().GetString(mustCLICommandFlagName(c, &c.FromSnapshot))
 	if err != nil {
 		return err
 	}
-	if snapshotID != "" {
+	fromFile, err := cmd.Flags().GetString(mustCLICommandFlagName(c, &c.FromFile))
+	if err != nil {
+		return err
+	}
+	if snapshotID != "" || fromFile != "" {
 		args = append(args, "", "")
 	}
 
-	return cliCommandDefaultPreRun(c, cmd, args)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if c.FromFile != "" {
+		if c.FromSnapshot != "" {
+			cmdExitOnUsageError(cmd, "--from-file and --from-snapshot are mutually exclusive")
+		}
+
+		if c.Async {
+			cmdExitOnUsageError(cmd, "--from-file and --async are mutually exclusive")
+		}
+	}
+
+	if c.Bucket != "" && c.FromFile == "" {
+		cmdExitOnUsageError(cmd, "--bucket can only be used with --from-file")
+	}
+
+	return nil
 }
 
 func (c *instanceTemplateRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) error {
@@ -110,14 +159,9 @@ func (c *instanceTemplateRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) err
 		template.Checksum = snapshotExport.MD5sum
 
 		// Pre-setting the new template properties from the source template.
-		instance, err := cs.GetInstance(ctx, c.Zone, *snapshot.InstanceID)
+		srcTemplate, err := snapshotSourceTemplate(ctx, c.Zone, snapshot)
 		if err != nil {
-			return fmt.Errorf("error retrieving Compute instance from snapshot: %w", err)
-		}
-
-		srcTemplate, err := cs.GetTemplate(ctx, c.Zone, *instance.TemplateID)
-		if err != nil {
-			return fmt.Errorf("error retrieving Compute instance template from snapshot: %w", err)
+			return err
 		}
 
 		template.BootMode = srcTemplate.BootMode
@@ -143,10 +187,47 @@ func (c *instanceTemplateRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) err
 		}
 	}
 
+	if c.FromFile != "" {
+		url, checksum, cleanup, err := c.uploadImage()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		template.URL = &url
+		template.Checksum = &checksum
+	}
+
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.BootMode)) {
 		template.BootMode = &c.BootMode
 	}
 
+	if c.Async {
+		resp, err := cs.RegisterTemplateWithResponse(exoapi.WithZone(ctx, c.Zone), oapi.RegisterTemplateJSONRequestBody{
+			BootMode:        (*oapi.RegisterTemplateJSONBodyBootMode)(template.BootMode),
+			Build:           template.Build,
+			Checksum:        utils.DefaultString(template.Checksum, ""),
+			DefaultUser:     template.DefaultUser,
+			Description:     template.Description,
+			Maintainer:      template.Maintainer,
+			Name:            *template.Name,
+			PasswordEnabled: *template.PasswordEnabled,
+			SshKeyEnabled:   *template.SSHKeyEnabled,
+			Url:             utils.DefaultString(template.URL, ""),
+			Version:         template.Version,
+		})
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+	}
+
 	decorateAsyncOperation(fmt.Sprintf("Registering template %q...", c.Name), func() {
 		template, err = cs.RegisterTemplate(ctx, c.Zone, template)
 	})
@@ -177,6 +258,86 @@ func (c *instanceTemplateRegisterCmd) cmdRun(cmd *cobra.Command, _ []string) err
 	return nil
 }
 
+// uploadImage uploads the local disk image file specified using the
+// `--from-file` flag to SOS, and returns a pre-signed URL to the uploaded
+// image along with its checksum. The returned cleanup function deletes the
+// uploaded image (and the temporary bucket, if any).
+func (c *instanceTemplateRegisterCmd) uploadImage() (string, string, func(), error) {
+	var checksum string
+
+	f, err := os.Open(c.FromFile)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer f.Close()
+
+	decorateAsyncOperation(fmt.Sprintf("Computing checksum of %q...", c.FromFile), func() {
+		h := md5.New()
+		if _, err = io.Copy(h, f); err != nil {
+			return
+		}
+		checksum = hex.EncodeToString(h.Sum(nil))
+	})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("unable to compute image checksum: %w", err)
+	}
+
+	storage, err := newStorageClient(storageClientOptWithZone(c.Zone))
+	if err != nil {
+		return "", "", nil, fmt.Errorf("unable to initialize storage client: %w", err)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", "", nil, err
+	}
+	tmpName := "exo-template-" + hex.EncodeToString(suffix)
+
+	// When uploading to an existing bucket, the image is stored under a
+	// unique prefix to avoid overwriting an existing object.
+	bucket, key := c.Bucket, path.Join(tmpName, filepath.Base(c.FromFile))
+	if bucket == "" {
+		bucket, key = tmpName, filepath.Base(c.FromFile)
+
+		if err := storage.createBucket(bucket, ""); err != nil {
+			return "", "", nil, fmt.Errorf("unable to create temporary bucket: %w", err)
+		}
+	}
+
+	cleanup := func() {
+		var err error
+		if c.Bucket == "" {
+			err = storage.deleteBucket(bucket, true)
+		} else {
+			_, err = storage.DeleteObject(gContext, &s3.DeleteObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(key),
+			})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to delete uploaded image sos://%s/%s: %s\n", bucket, key, err)
+		}
+	}
+
+	if err := storage.uploadFile(bucket, c.FromFile, key, ""); err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("unable to upload image: %w", err)
+	}
+	// uploadFile() doesn't report an interrupted upload as an error.
+	if err := gContext.Err(); err != nil {
+		cleanup()
+		return "", "", nil, err
+	}
+
+	url, err := storage.genPresignedURL("get", bucket, key, time.Duration(c.Timeout)*time.Second)
+	if err != nil {
+		cleanup()
+		return "", "", nil, fmt.Errorf("unable to generate pre-signed URL: %w", err)
+	}
+
+	return url, checksum, cleanup, nil
+}
+
 func init() {
 	cobra.CheckErr(registerCLICommand(instanceTemplateCmd, &instanceTemplateRegisterCmd{
 		cliCommandSettings: defaultCLICmdSettings(),
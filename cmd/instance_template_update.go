@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type instanceTemplateUpdateCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"update"`
+
+	Template string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Description string `cli-usage:"template description"`
+	Name        string `cli-usage:"template name"`
+	Zone        string `cli-short:"z" cli-usage:"template zone"`
+}
+
+func (c *instanceTemplateUpdateCmd) cmdAliases() []string { return nil }
+
+func (c *instanceTemplateUpdateCmd) cmdShort() string {
+	return "Update a Compute instance template"
+}
+
+func (c *instanceTemplateUpdateCmd) cmdLong() string {
+	return fmt.Sprintf(`This command updates a Compute instance template.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&instanceTemplateShowOutput{}), ", "))
+}
+
+func (c *instanceTemplateUpdateCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceTemplateUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
+	var updated bool
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	template, err := cs.FindTemplate(ctx, c.Zone, c.Template, "private")
+	if err != nil {
+		return fmt.Errorf("no private template %q found in zone %s", c.Template, c.Zone)
+	}
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Description)) {
+		template.Description = &c.Description
+		updated = true
+	}
+
+	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.Name)) {
+		template.Name = &c.Name
+		updated = true
+	}
+
+	if updated {
+		decorateAsyncOperation(fmt.Sprintf("Updating template %q...", c.Template), func() {
+			err = cs.UpdateTemplate(ctx, c.Zone, template)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !gQuiet {
+		return (&instanceTemplateShowCmd{
+			cliCommandSettings: c.cliCommandSettings,
+			Template:           *template.ID,
+			Visibility:         "private",
+			Zone:               c.Zone,
+		}).cmdRun(nil, nil)
+	}
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceTemplateCmd, &instanceTemplateUpdateCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshTunnelKeepaliveInterval = 30 * time.Second
+	sshTunnelKeepaliveTimeout  = 15 * time.Second
+	sshTunnelMaxRetryInterval  = 30 * time.Second
+)
+
+// parseSSHLocalForward parses a local port forwarding specification in the
+// ssh(1) -L option format ([BIND-ADDRESS:]PORT:HOST:HOST-PORT, IPv6 addresses
+// enclosed in square brackets), and returns the local address to listen on
+// and the remote address to forward connections to.
+func parseSSHLocalForward(spec string) (string, string, error) {
+	parts := make([]string, 0)
+	for rest := spec; rest != ""; {
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return "", "", fmt.Errorf("invalid forward specification %q", spec)
+			}
+			parts = append(parts, rest[1:end])
+			rest = strings.TrimPrefix(rest[end+1:], ":")
+			continue
+		}
+
+		i := strings.Index(rest, ":")
+		if i < 0 {
+			parts = append(parts, rest)
+			break
+		}
+		parts = append(parts, rest[:i])
+		rest = rest[i+1:]
+	}
+
+	switch len(parts) {
+	case 3:
+		parts = append([]string{"localhost"}, parts...)
+	case 4:
+	default:
+		return "", "", fmt.Errorf("invalid forward specification %q", spec)
+	}
+
+	for _, port := range []string{parts[1], parts[3]} {
+		if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+			return "", "", fmt.Errorf("invalid port %q in forward specification %q", port, spec)
+		}
+	}
+
+	return net.JoinHostPort(parts[0], parts[1]), net.JoinHostPort(parts[2], parts[3]), nil
+}
+
+// sshTunnel maintains an SSH connection to a target used to forward network
+// connections, transparently re-establishing it if lost.
+type sshTunnel struct {
+	target *instanceSSHTarget
+
+	mu     sync.Mutex
+	client *ssh.Client
+	done   chan struct{} // closed when client is reset, stopping its keepalive
+}
+
+// getClient returns the current SSH connection, establishing it if needed.
+func (t *sshTunnel) getClient() (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	client, err := t.target.dial()
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+	t.done = make(chan struct{})
+	go t.keepalive(client, t.done)
+
+	return client, nil
+}
+
+// reset closes the SSH connection specified if it is still the current one.
+func (t *sshTunnel) reset(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == client {
+		t.closeClient()
+	}
+}
+
+// close closes the current SSH connection, if any.
+func (t *sshTunnel) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		t.closeClient()
+	}
+}
+
+// closeClient closes the current SSH connection and stops its keepalive.
+// The caller must hold the tunnel lock.
+func (t *sshTunnel) closeClient() {
+	close(t.done)
+	_ = t.client.Close()
+	t.client = nil
+}
+
+// keepalive periodically checks that the SSH connection is alive, and
+// re-establishes it with an exponential backoff if it isn't. It returns when
+// done is closed, i.e. once the connection has been reset by someone else.
+func (t *sshTunnel) keepalive(client *ssh.Client, done chan struct{}) {
+	ticker := time.NewTicker(sshTunnelKeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-gContext.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if err := sshKeepalive(client, sshTunnelKeepaliveTimeout); err == nil {
+			continue
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		t.reset(client)
+		fmt.Fprintf(os.Stderr, "warning: connection to %s lost, reconnecting\n", t.target.host)
+
+		for retry := time.Second; ; retry *= 2 {
+			if retry > sshTunnelMaxRetryInterval {
+				retry = sshTunnelMaxRetryInterval
+			}
+
+			if _, err := t.getClient(); err == nil {
+				return
+			}
+
+			select {
+			case <-gContext.Done():
+				return
+			case <-time.After(retry):
+			}
+		}
+	}
+}
+
+// sshKeepalive sends a keepalive request on the SSH connection, and returns
+// an error if no reply is received before the timeout expires.
+func sshKeepalive(client *ssh.Client, timeout time.Duration) error {
+	res := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		res <- err
+	}()
+
+	select {
+	case err := <-res:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no keepalive reply received in %s", timeout)
+	}
+}
+
+// dial opens a connection to the specified address through the SSH tunnel.
+func (t *sshTunnel) dial(addr string) (net.Conn, error) {
+	var err error
+
+	// If the SSH connection appears to be broken, try again once with a
+	// new one.
+	for attempt := 0; attempt < 2; attempt++ {
+		var client *ssh.Client
+		if client, err = t.getClient(); err != nil {
+			return nil, err
+		}
+
+		var conn net.Conn
+		if conn, err = client.Dial("tcp", addr); err == nil {
+			return conn, nil
+		}
+
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) {
+			return nil, err
+		}
+
+		t.reset(client)
+	}
+
+	return nil, err
+}
+
+// forward accepts connections on the listener and pipes them to the remote
+// connections returned by the handle function. Once one side is done
+// sending, the write direction of the other side is closed, and the
+// connections are closed once both directions are done.
+func (t *sshTunnel) forward(l net.Listener, handle func(net.Conn) (net.Conn, error)) {
+	for {
+		local, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer local.Close()
+
+			remote, err := handle(local)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+				return
+			}
+			defer remote.Close()
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() { defer wg.Done(); pipeConn(remote, local) }()
+			go func() { defer wg.Done(); pipeConn(local, remote) }()
+			wg.Wait()
+		}()
+	}
+}
+
+// pipeConn copies data from src to dst until src is done sending, then
+// closes the write direction of dst if supported or dst otherwise.
+func pipeConn(dst, src net.Conn) {
+	_, _ = io.Copy(dst, src)
+
+	if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+		return
+	}
+	_ = dst.Close()
+}
+
+// socks5Handshake performs a SOCKS5 server handshake (RFC 1928, CONNECT
+// command without authentication only) on conn, and returns the remote
+// connection opened through the tunnel to the requested destination.
+func (t *sshTunnel) socks5Handshake(conn net.Conn) (net.Conn, error) {
+	reply := func(status byte) {
+		_, _ = conn.Write([]byte{5, status, 0, 1, 0, 0, 0, 0, 0, 0})
+	}
+
+	buf := make([]byte, 256)
+
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil || buf[0] != 5 {
+		return nil, fmt.Errorf("SOCKS: invalid handshake")
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return nil, fmt.Errorf("SOCKS: invalid handshake")
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil || buf[0] != 5 {
+		return nil, fmt.Errorf("SOCKS: invalid request")
+	}
+	if buf[1] != 1 {
+		reply(7) // Command not supported
+		return nil, fmt.Errorf("SOCKS: unsupported command %d", buf[1])
+	}
+
+	var host string
+	switch buf[3] {
+	case 1: // IPv4
+		if _, err := io.ReadFull(conn, buf[:net.IPv4len]); err != nil {
+			return nil, fmt.Errorf("SOCKS: invalid request")
+		}
+		host = net.IP(buf[:net.IPv4len]).String()
+
+	case 3: // Domain name
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return nil, fmt.Errorf("SOCKS: invalid request")
+		}
+		n := int(buf[0])
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return nil, fmt.Errorf("SOCKS: invalid request")
+		}
+		host = string(buf[:n])
+
+	case 4: // IPv6
+		if _, err := io.ReadFull(conn, buf[:net.IPv6len]); err != nil {
+			return nil, fmt.Errorf("SOCKS: invalid request")
+		}
+		host = net.IP(buf[:net.IPv6len]).String()
+
+	default:
+		reply(8) // Address type not supported
+		return nil, fmt.Errorf("SOCKS: unsupported address type %d", buf[3])
+	}
+
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return nil, fmt.Errorf("SOCKS: invalid request")
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+
+	remote, err := t.dial(addr)
+	if err != nil {
+		reply(5) // Connection refused
+		return nil, fmt.Errorf("unable to connect to %s: %w", addr, err)
+	}
+	reply(0)
+
+	return remote, nil
+}
+
+type instanceTunnelCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"tunnel"`
+
+	Instance string `cli-arg:"#" cli-usage:"INSTANCE-NAME|ID"`
+
+	DynamicForward string   `cli-short:"D" cli-usage:"[BIND-ADDRESS:]PORT to listen on for SOCKS5 proxy connections forwarded through the instance"`
+	IPv6           bool     `cli-flag:"ipv6" cli-short:"6" cli-usage:"connect to the instance via its IPv6 address"`
+	LocalForward   []string `cli-short:"L" cli-usage:"[BIND-ADDRESS:]PORT:HOST:HOST-PORT local port forwarding specification (can be repeated multiple times)"`
+	Login          string   `cli-short:"l" cli-usage:"SSH username to use for logging in (default: instance template default username)"`
+	Zone           string   `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceTunnelCmd) cmdAliases() []string { return nil }
+
+func (c *instanceTunnelCmd) cmdShort() string {
+	return "Forward local ports to a Compute instance via SSH"
+}
+
+func (c *instanceTunnelCmd) cmdLong() string {
+	return `This command forwards local TCP ports through an SSH connection to a Compute
+instance, similar to the ssh(1) -L and -D options (without requiring the ssh(1)
+command). The SSH connection is automatically re-established if lost. The
+command runs until interrupted.
+
+Examples:
+
+    exo compute instance tunnel my-instance -L 5432:localhost:5432
+    exo compute instance tunnel my-instance -L 8080:10.0.0.1:80 -L 8443:10.0.0.1:443
+    exo compute instance tunnel my-instance -D 1080
+
+The SSH connection is authenticated the same way as "exo compute instance exec"
+(see "exo compute instance exec --help").
+`
+}
+
+func (c *instanceTunnelCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	if err := cliCommandDefaultPreRun(c, cmd, args); err != nil {
+		return err
+	}
+
+	if len(c.LocalForward) == 0 && c.DynamicForward == "" {
+		return fmt.Errorf("at least one of --local-forward or --dynamic-forward must be specified")
+	}
+
+	return nil
+}
+
+func (c *instanceTunnelCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	target, err := newInstanceSSHTarget(ctx, instance, c.Login, c.IPv6, "", newResourceCache())
+	if err != nil {
+		return err
+	}
+
+	tunnel := &sshTunnel{target: target}
+
+	// Establishing the SSH connection upfront to fail early in case of
+	// connection or authentication error.
+	if _, err := tunnel.getClient(); err != nil {
+		return fmt.Errorf("unable to connect to instance: %w", err)
+	}
+	defer tunnel.close()
+
+	listeners := make([]net.Listener, 0)
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+
+	for _, spec := range c.LocalForward {
+		local, remote, err := parseSSHLocalForward(spec)
+		if err != nil {
+			return err
+		}
+
+		l, err := net.Listen("tcp", local)
+		if err != nil {
+			return fmt.Errorf("unable to listen on %s: %w", local, err)
+		}
+		listeners = append(listeners, l)
+
+		go tunnel.forward(l, func(net.Conn) (net.Conn, error) {
+			conn, err := tunnel.dial(remote)
+			if err != nil {
+				return nil, fmt.Errorf("unable to connect to %s: %w", remote, err)
+			}
+			return conn, nil
+		})
+
+		if !gQuiet {
+			fmt.Fprintf(os.Stderr, "Forwarding %s to %s via %s\n", l.Addr(), remote, *instance.Name)
+		}
+	}
+
+	if c.DynamicForward != "" {
+		local := c.DynamicForward
+		if _, err := strconv.ParseUint(local, 10, 16); err == nil {
+			local = net.JoinHostPort("localhost", local)
+		}
+
+		l, err := net.Listen("tcp", local)
+		if err != nil {
+			return fmt.Errorf("unable to listen on %s: %w", local, err)
+		}
+		listeners = append(listeners, l)
+
+		go tunnel.forward(l, tunnel.socks5Handshake)
+
+		if !gQuiet {
+			fmt.Fprintf(os.Stderr, "SOCKS5 proxy listening on %s via %s\n", l.Addr(), *instance.Name)
+		}
+	}
+
+	<-gContext.Done()
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceCmd, &instanceTunnelCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSSHLocalForward(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           string
+		expectedLocal  string
+		expectedRemote string
+		wantErr        bool
+	}{
+		{
+			name:           "ok",
+			spec:           "5432:localhost:5432",
+			expectedLocal:  "localhost:5432",
+			expectedRemote: "localhost:5432",
+		},
+		{
+			name:           "ok with bind address",
+			spec:           "0.0.0.0:8080:10.0.0.1:80",
+			expectedLocal:  "0.0.0.0:8080",
+			expectedRemote: "10.0.0.1:80",
+		},
+		{
+			name:           "ok with IPv6 addresses",
+			spec:           "[::1]:8080:[2001:db8::1]:80",
+			expectedLocal:  "[::1]:8080",
+			expectedRemote: "[2001:db8::1]:80",
+		},
+		{
+			name:    "error missing host port",
+			spec:    "5432:localhost",
+			wantErr: true,
+		},
+		{
+			name:    "error invalid port",
+			spec:    "5432:localhost:postgres",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, remote, err := parseSSHLocalForward(tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedLocal, local)
+			require.Equal(t, tt.expectedRemote, remote)
+		})
+	}
+}
+
+func Test_sshTunnel_forward_halfClose(t *testing.T) {
+	// The upstream server replies only once the client is done sending.
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer upstream.Close()
+	go func() {
+		conn, err := upstream.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		_, _ = conn.Write(append([]byte("reply:"), data...))
+	}()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go (&sshTunnel{}).forward(l, func(net.Conn) (net.Conn, error) {
+		return net.Dial("tcp", upstream.Addr().String())
+	})
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+
+	res, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "reply:ping", string(res))
+}
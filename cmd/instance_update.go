@@ -16,8 +16,9 @@ type instanceUpdateCmd struct {
 
 	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
 
-	CloudInitFile     string            `cli-flag:"cloud-init" cli-short:"c" cli-usage:"instance cloud-init user data configuration file path"`
+	CloudInitFile     []string          `cli-flag:"cloud-init" cli-short:"c" cli-usage:"instance cloud-init user data configuration file path (can be specified multiple times)"`
 	CloudInitCompress bool              `cli-flag:"cloud-init-compress" cli-usage:"compress instance cloud-init user data"`
+	CloudInitVars     map[string]string `cli-flag:"cloud-init-var" cli-usage:"instance cloud-init user data template variable (format: key=value)"`
 	Labels            map[string]string `cli-flag:"label" cli-usage:"instance label (format: key=value)"`
 	Name              string            `cli-short:"n" cli-usage:"instance name"`
 	Zone              string            `cli-short:"z" cli-usage:"instance zone"`
@@ -31,7 +32,10 @@ func (c *instanceUpdateCmd) cmdShort() string { return "Update an Instance " }
 func (c *instanceUpdateCmd) cmdLong() string {
 	return fmt.Sprintf(`This command updates an Instance .
 
+%s
+
 Supported output template annotations: %s`,
+		cloudInitHelp,
 		strings.Join(outputterTemplateAnnotations(&instanceShowOutput{}), ", "),
 	)
 }
@@ -65,7 +69,11 @@ func (c *instanceUpdateCmd) cmdRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	if cmd.Flags().Changed(mustCLICommandFlagName(c, &c.CloudInitFile)) {
-		userData, err := getUserDataFromFile(c.CloudInitFile, c.CloudInitCompress)
+		userData, err := buildUserData(c.CloudInitFile, &cloudInitTemplateData{
+			Name: *instance.Name,
+			Zone: c.Zone,
+			Vars: c.CloudInitVars,
+		}, c.CloudInitCompress)
 		if err != nil {
 			return fmt.Errorf("error parsing cloud-init user data: %w", err)
 		}
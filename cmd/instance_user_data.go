@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+var instanceUserDataCmd = &cobra.Command{
+	Use:   "user-data",
+	Short: "Manage Compute instances cloud-init user data",
+}
+
+func init() {
+	instanceCmd.AddCommand(instanceUserDataCmd)
+}
+
+type instanceUserDataShowCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"show"`
+
+	Instance string `cli-arg:"#" cli-usage:"NAME|ID"`
+
+	Zone string `cli-short:"z" cli-usage:"instance zone"`
+}
+
+func (c *instanceUserDataShowCmd) cmdAliases() []string { return gShowAlias }
+
+func (c *instanceUserDataShowCmd) cmdShort() string {
+	return "Show a Compute instance cloud-init user data"
+}
+
+func (c *instanceUserDataShowCmd) cmdLong() string {
+	return `This command shows the cloud-init user data a Compute instance has been
+created with, transparently decoding compressed user data.`
+}
+
+func (c *instanceUserDataShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *instanceUserDataShowCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	instance, err := cs.FindInstance(ctx, c.Zone, c.Instance)
+	if err != nil {
+		if errors.Is(err, exoapi.ErrNotFound) {
+			return fmt.Errorf("resource not found in zone %q", c.Zone)
+		}
+		return err
+	}
+
+	if instance.UserData == nil || *instance.UserData == "" {
+		return fmt.Errorf("instance %q has no user data", *instance.Name)
+	}
+
+	userData, err := decodeUserData(*instance.UserData)
+	if err != nil {
+		return fmt.Errorf("error decoding user data: %w", err)
+	}
+
+	fmt.Print(userData)
+
+	return nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(instanceUserDataCmd, &instanceUserDataShowCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
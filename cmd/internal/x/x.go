@@ -1,6 +1,7 @@
 package x
 
 import (
+	"net/http"
 	"os"
 
 	exoapi "github.com/exoscale/egoscale/v2/api"
@@ -58,3 +59,11 @@ func SetClientUserAgent(ua string) {
 		h.Next(ctx)
 	})
 }
+
+// SetClientTransport sets the HTTP transport used to perform outgoing requests.
+func SetClientTransport(rt http.RoundTripper) {
+	cli.Client.UseRequest(func(ctx *context.Context, h context.Handler) {
+		ctx.Client.Transport = rt
+		h.Next(ctx)
+	})
+}
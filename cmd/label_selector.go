@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// labelSelectorHelp documents the label selector syntax in the help of the
+// commands supporting the --selector flag.
+const labelSelectorHelp = `Label selectors are comma-separated lists of requirements, all of which must
+be satisfied by the instances labels: "KEY=VALUE", "KEY!=VALUE", "KEY" (label
+is set) and "!KEY" (label is not set).`
+
+type labelSelectorOp int
+
+const (
+	labelSelectorOpEquals labelSelectorOp = iota
+	labelSelectorOpNotEquals
+	labelSelectorOpExists
+	labelSelectorOpNotExists
+)
+
+type labelSelectorRequirement struct {
+	key   string
+	value string
+	op    labelSelectorOp
+}
+
+// labelSelector represents a set of requirements on resource labels, all of
+// which must be satisfied for a resource to match the selector.
+type labelSelector []labelSelectorRequirement
+
+// parseLabelSelector parses a comma-separated list of label requirements.
+// Supported requirements are:
+//
+//   - "KEY=VALUE": label KEY is set with value VALUE
+//   - "KEY!=VALUE": label KEY is not set, or set with a value other than VALUE
+//   - "KEY": label KEY is set
+//   - "!KEY": label KEY is not set
+func parseLabelSelector(s string) (labelSelector, error) {
+	selector := make(labelSelector, 0)
+
+	for _, r := range strings.Split(s, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		var req labelSelectorRequirement
+
+		switch {
+		case strings.Contains(r, "!="):
+			parts := strings.SplitN(r, "!=", 2)
+			req = labelSelectorRequirement{key: parts[0], value: parts[1], op: labelSelectorOpNotEquals}
+
+		case strings.Contains(r, "="):
+			parts := strings.SplitN(r, "=", 2)
+			req = labelSelectorRequirement{key: parts[0], value: parts[1], op: labelSelectorOpEquals}
+
+		case strings.HasPrefix(r, "!"):
+			req = labelSelectorRequirement{key: strings.TrimPrefix(r, "!"), op: labelSelectorOpNotExists}
+
+		default:
+			req = labelSelectorRequirement{key: r, op: labelSelectorOpExists}
+		}
+
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if req.key == "" || strings.ContainsAny(req.key, "!=") {
+			return nil, fmt.Errorf("invalid label selector requirement %q", r)
+		}
+
+		selector = append(selector, req)
+	}
+
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("empty label selector")
+	}
+
+	return selector, nil
+}
+
+// matches returns true if the specified labels satisfy all the selector
+// requirements.
+func (s labelSelector) matches(labels map[string]string) bool {
+	for _, req := range s {
+		v, ok := labels[req.key]
+
+		switch req.op {
+		case labelSelectorOpEquals:
+			if !ok || v != req.value {
+				return false
+			}
+		case labelSelectorOpNotEquals:
+			if ok && v == req.value {
+				return false
+			}
+		case labelSelectorOpExists:
+			if !ok {
+				return false
+			}
+		case labelSelectorOpNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		expected labelSelector
+		wantErr  bool
+	}{
+		{
+			name:     "ok",
+			selector: "env=prod, role!=db,backup,!legacy",
+			expected: labelSelector{
+				{key: "env", value: "prod", op: labelSelectorOpEquals},
+				{key: "role", value: "db", op: labelSelectorOpNotEquals},
+				{key: "backup", op: labelSelectorOpExists},
+				{key: "legacy", op: labelSelectorOpNotExists},
+			},
+		},
+		{
+			name:     "error empty selector",
+			selector: " , ",
+			wantErr:  true,
+		},
+		{
+			name:     "error missing key",
+			selector: "=prod",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseLabelSelector(tt.selector)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_labelSelector_matches(t *testing.T) {
+	selector, err := parseLabelSelector("env=prod,role!=db,!legacy")
+	require.NoError(t, err)
+
+	require.True(t, selector.matches(map[string]string{"env": "prod", "role": "web"}))
+	require.True(t, selector.matches(map[string]string{"env": "prod"}))
+	require.False(t, selector.matches(map[string]string{"env": "prod", "role": "db"}))
+	require.False(t, selector.matches(map[string]string{"env": "prod", "legacy": "yes"}))
+	require.False(t, selector.matches(map[string]string{"env": "dev"}))
+	require.False(t, selector.matches(nil))
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/exoscale/cli/table"
+	exov2 "github.com/exoscale/egoscale/v2"
+	"github.com/hashicorp/go-multierror"
+)
+
+// cliCommandMultiAccount is an optional interface that can be implemented by
+// cliCommand implementers supporting to be executed against multiple accounts
+// at once (see the "--accounts" and "--all-accounts" global flags). The
+// implementation must only use the API client and account configuration
+// provided instead of the global ones, as it is executed concurrently for
+// each account.
+type cliCommandMultiAccount interface {
+	cmdRunForAccount(client *exov2.Client, acc *account) (outputter, error)
+}
+
+// multiAccountMode returns true if the user requested to execute the command
+// against multiple accounts.
+func multiAccountMode() bool {
+	return gAllAccounts || len(gAccounts) > 0
+}
+
+// selectedAccounts returns the configured accounts targeted by the
+// "--accounts" or "--all-accounts" global flags.
+func selectedAccounts() ([]*account, error) {
+	if gAllAccount == nil {
+		return nil, fmt.Errorf("no accounts configured")
+	}
+
+	accounts := make([]*account, 0)
+
+	if gAllAccounts {
+		for i := range gAllAccount.Accounts {
+			accounts = append(accounts, &gAllAccount.Accounts[i])
+		}
+		return accounts, nil
+	}
+
+	for _, name := range gAccounts {
+		acc := getAccountByName(name)
+		if acc == nil {
+			return nil, fmt.Errorf("account %q not found", name)
+		}
+		accounts = append(accounts, acc)
+	}
+
+	return accounts, nil
+}
+
+// forEachAccount executes the function f for each specified account with a
+// dedicated API client, and return a multierror.Error containing all errors
+// that may have occurred during execution.
+func forEachAccount(accounts []*account, f func(acc *account, client *exov2.Client) error) error {
+	meg := new(multierror.Group)
+
+	for _, acc := range accounts {
+		acc := acc
+		meg.Go(func() error {
+			client, err := newClient(acc)
+			if err != nil {
+				return fmt.Errorf("account %s: %w", acc.Name, err)
+			}
+
+			if err := f(acc, client.Client); err != nil {
+				return fmt.Errorf("account %s: %w", acc.Name, err)
+			}
+
+			return nil
+		})
+	}
+
+	return meg.Wait().ErrorOrNil()
+}
+
+// cmdRunMultiAccount executes the command c against each of the accounts
+// selected by the user, and outputs the merged results.
+func cmdRunMultiAccount(c cliCommandMultiAccount) error {
+	accounts, err := selectedAccounts()
+	if err != nil {
+		return err
+	}
+
+	var (
+		out = make(multiAccountOutput, 0)
+		mu  sync.Mutex
+	)
+
+	err = forEachAccount(accounts, func(acc *account, client *exov2.Client) error {
+		o, err := c.cmdRunForAccount(client, acc)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		out = append(out, multiAccountOutputItem{account: acc.Name, out: o})
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"warning: errors during execution, results might be incomplete.\n%s\n", err) // nolint:golint
+	}
+
+	// Preserve the order in which the accounts have been specified.
+	sorted := make(multiAccountOutput, 0, len(out))
+	for _, acc := range accounts {
+		for _, item := range out {
+			if item.account == acc.Name {
+				sorted = append(sorted, item)
+			}
+		}
+	}
+
+	return output(&sorted, nil)
+}
+
+type multiAccountOutputItem struct {
+	account string
+	out     outputter
+}
+
+// multiAccountOutput represents the merged results of a command executed
+// against multiple accounts: each result item is prefixed with an "Account"
+// column/field.
+type multiAccountOutput []multiAccountOutputItem
+
+// items returns the list of individual result items (i.e. a single item
+// for "show" commands, or each item of the list for "list" commands) along
+// with the name of the account they belong to.
+func (o *multiAccountOutput) items() ([]string, []reflect.Value) {
+	var (
+		accounts = make([]string, 0)
+		items    = make([]reflect.Value, 0)
+	)
+
+	for _, r := range *o {
+		if r.out == nil {
+			continue
+		}
+
+		v := reflect.Indirect(reflect.ValueOf(r.out))
+		if v.Kind() == reflect.Slice {
+			for i := 0; i < v.Len(); i++ {
+				accounts = append(accounts, r.account)
+				items = append(items, reflect.Indirect(v.Index(i)))
+			}
+			continue
+		}
+
+		accounts = append(accounts, r.account)
+		items = append(items, v)
+	}
+
+	return accounts, items
+}
+
+func (o *multiAccountOutput) toJSON() {
+	out := make([]map[string]interface{}, 0)
+
+	accounts, items := o.items()
+	for i := range items {
+		j, err := json.Marshal(items[i].Interface())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: unable to encode output to JSON: %s\n", err)
+			os.Exit(1)
+		}
+
+		item := make(map[string]interface{})
+		if err := json.Unmarshal(j, &item); err != nil {
+			fmt.Fprintf(os.Stderr, "error: unable to encode output to JSON: %s\n", err)
+			os.Exit(1)
+		}
+		item["account"] = accounts[i]
+
+		out = append(out, item)
+	}
+
+	outputJSON(out)
+}
+
+func (o *multiAccountOutput) toText() {
+	accounts, items := o.items()
+	if len(items) == 0 {
+		return
+	}
+
+	tpl := gOutputTemplate
+	if tpl == "" {
+		tplFields := append([]string{".Account"}, outputterTemplateAnnotations(items[0].Interface())...)
+		for i := range tplFields {
+			tplFields[i] = "{{" + tplFields[i] + "}}"
+		}
+		tpl = strings.Join(tplFields, "\t")
+	}
+
+	t, err := template.New("out").Parse(tpl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to encode output in plaintext using template: %s\n", err)
+		os.Exit(1)
+	}
+
+	for i := range items {
+		data := map[string]interface{}{"Account": accounts[i]}
+		for f := 0; f < items[i].NumField(); f++ {
+			data[items[i].Type().Field(f).Name] = items[i].Field(f).Interface()
+		}
+
+		if err := t.Execute(os.Stdout, data); err != nil {
+			fmt.Fprintf(os.Stderr, "error: unable to encode output using template: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println()
+	}
+}
+
+func (o *multiAccountOutput) toTable() {
+	accounts, items := o.items()
+	if len(items) == 0 {
+		return
+	}
+
+	tab := table.NewTable(os.Stdout)
+	tab.SetHeader(append([]string{"Account"}, outputTableHeaders(items[0].Type())...))
+
+	for i := range items {
+		tab.Append(append([]string{accounts[i]}, outputTableRow(items[i])...))
+	}
+
+	tab.Render()
+}
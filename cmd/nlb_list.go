@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/exoscale/cli/utils"
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -47,12 +48,16 @@ func (c *nlbListCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
 }
 
 func (c *nlbListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *nlbListCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
 	var zones []string
 
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(client, acc)
 	}
 
 	out := make(nlbListOutput, 0)
@@ -66,9 +71,9 @@ func (c *nlbListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		done <- struct{}{}
 	}()
 	err := forEachZone(zones, func(zone string) error {
-		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, zone))
 
-		list, err := cs.ListNetworkLoadBalancers(ctx, zone)
+		list, err := client.ListNetworkLoadBalancers(ctx, zone)
 		if err != nil {
 			return fmt.Errorf("unable to list Network Load Balancers in zone %s: %w", zone, err)
 		}
@@ -92,7 +97,7 @@ func (c *nlbListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	close(res)
 	<-done
 
-	return c.outputFunc(&out, nil)
+	return &out, nil
 }
 
 func init() {
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/exoscale/cli/utils"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+var operationCmd = &cobra.Command{
+	Use:     "operation",
+	Short:   "Asynchronous operations management",
+	Aliases: []string{"op"},
+}
+
+// asyncOperationFromResponse returns the asynchronous operation returned by
+// an API call performed in "--async" mode, or an error if the API call did
+// not succeed.
+func asyncOperationFromResponse(resp interface {
+	StatusCode() int
+	Status() string
+}, op *oapi.Operation,
+) (*oapi.Operation, error) {
+	if resp.StatusCode() != http.StatusOK || op == nil {
+		return nil, fmt.Errorf("API request error: unexpected status %s", resp.Status())
+	}
+
+	return op, nil
+}
+
+func newOperationShowOutput(op *oapi.Operation, zone string) *operationShowOutput {
+	out := operationShowOutput{
+		ID:      utils.DefaultString(op.Id, ""),
+		Message: utils.DefaultString(op.Message, ""),
+		Zone:    zone,
+	}
+
+	if op.State != nil {
+		out.State = string(*op.State)
+	}
+
+	if op.Reason != nil {
+		out.Reason = string(*op.Reason)
+	}
+
+	if op.Reference != nil {
+		out.ReferenceID = utils.DefaultString(op.Reference.Id, "")
+		out.ReferenceCommand = utils.DefaultString(op.Reference.Command, "")
+		out.ReferenceLink = utils.DefaultString(op.Reference.Link, "")
+	}
+
+	return &out
+}
+
+func init() {
+	RootCmd.AddCommand(operationCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/spf13/cobra"
+)
+
+type operationShowOutput struct {
+	ID               string `json:"id"`
+	State            string `json:"state"`
+	Message          string `json:"message"`
+	Reason           string `json:"reason"`
+	ReferenceID      string `json:"reference_id" outputLabel:"Reference ID"`
+	ReferenceCommand string `json:"reference_command"`
+	ReferenceLink    string `json:"reference_link"`
+	Zone             string `json:"zone"`
+}
+
+func (o *operationShowOutput) Type() string { return "Operation" }
+func (o *operationShowOutput) toJSON()      { outputJSON(o) }
+func (o *operationShowOutput) toText()      { outputText(o) }
+func (o *operationShowOutput) toTable()     { outputTable(o) }
+
+type operationShowCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"show"`
+
+	ID string `cli-arg:"#"`
+
+	Zone string `cli-short:"z" cli-usage:"operation zone"`
+}
+
+func (c *operationShowCmd) cmdAliases() []string { return gShowAlias }
+
+func (c *operationShowCmd) cmdShort() string { return "Show an asynchronous operation details" }
+
+func (c *operationShowCmd) cmdLong() string {
+	return fmt.Sprintf(`This command shows an asynchronous operation details.
+
+Supported output template annotations: %s`,
+		strings.Join(outputterTemplateAnnotations(&operationShowOutput{}), ", "))
+}
+
+func (c *operationShowCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *operationShowCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	resp, err := cs.GetOperationWithResponse(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	op, err := asyncOperationFromResponse(resp, resp.JSON200)
+	if err != nil {
+		return err
+	}
+
+	return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(operationCmd, &operationShowCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
+	"github.com/spf13/cobra"
+)
+
+type operationWaitCmd struct {
+	cliCommandSettings `cli-cmd:"-"`
+
+	_ bool `cli-cmd:"wait"`
+
+	ID string `cli-arg:"#"`
+
+	Timeout int64  `cli-usage:"maximum duration in seconds to wait for the operation to complete (0: no limit)"`
+	Zone    string `cli-short:"z" cli-usage:"operation zone"`
+}
+
+func (c *operationWaitCmd) cmdAliases() []string { return nil }
+
+func (c *operationWaitCmd) cmdShort() string {
+	return "Wait for an asynchronous operation to complete"
+}
+
+func (c *operationWaitCmd) cmdLong() string {
+	return `This command waits for an asynchronous operation to complete, then shows
+its details. The command exits with an error status if the operation did not
+succeed.`
+}
+
+func (c *operationWaitCmd) cmdPreRun(cmd *cobra.Command, args []string) error {
+	cmdSetZoneFlagFromDefault(cmd)
+	return cliCommandDefaultPreRun(c, cmd, args)
+}
+
+func (c *operationWaitCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, c.Zone))
+
+	var (
+		op  *oapi.Operation
+		err error
+	)
+	decorateAsyncOperation(fmt.Sprintf("Waiting for operation %s to complete...", c.ID), func() {
+		op, err = waitAsyncOperation(ctx, c.ID, time.Duration(c.Timeout)*time.Second)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.outputFunc(newOperationShowOutput(op, c.Zone), nil); err != nil {
+		return err
+	}
+
+	if state := string(*op.State); state != "success" {
+		return fmt.Errorf("operation %s: %s", c.ID, state)
+	}
+
+	return nil
+}
+
+// waitAsyncOperation polls the asynchronous operation specified until it is
+// no longer pending, and returns its final state.
+func waitAsyncOperation(ctx context.Context, id string, timeout time.Duration) (*oapi.Operation, error) {
+	res, err := oapi.NewPoller().
+		WithTimeout(timeout).
+		Poll(ctx, func(ctx context.Context) (bool, interface{}, error) {
+			resp, err := cs.GetOperationWithResponse(ctx, id)
+			if err != nil {
+				return true, nil, err
+			}
+
+			op, err := asyncOperationFromResponse(resp, resp.JSON200)
+			if err != nil {
+				return true, nil, err
+			}
+
+			if op.State != nil && *op.State == "pending" {
+				return false, nil, nil
+			}
+
+			return true, op, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*oapi.Operation), nil
+}
+
+func init() {
+	cobra.CheckErr(registerCLICommand(operationCmd, &operationWaitCmd{
+		cliCommandSettings: defaultCLICmdSettings(),
+	}))
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
@@ -45,12 +46,16 @@ func (c *privateNetworkListCmd) cmdPreRun(cmd *cobra.Command, args []string) err
 }
 
 func (c *privateNetworkListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *privateNetworkListCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
 	var zones []string
 
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(client, acc)
 	}
 
 	out := make(privateNetworkListOutput, 0)
@@ -64,9 +69,9 @@ func (c *privateNetworkListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		done <- struct{}{}
 	}()
 	err := forEachZone(zones, func(zone string) error {
-		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(gCurrentAccount.Environment, zone))
+		ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, zone))
 
-		list, err := cs.ListPrivateNetworks(ctx, zone)
+		list, err := client.ListPrivateNetworks(ctx, zone)
 		if err != nil {
 			return fmt.Errorf("unable to list Private Networks in zone %s: %w", zone, err)
 		}
@@ -89,7 +94,7 @@ func (c *privateNetworkListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	close(res)
 	<-done
 
-	return c.outputFunc(&out, nil)
+	return &out, nil
 }
 
 func init() {
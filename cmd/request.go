@@ -214,15 +214,33 @@ func asyncRequest(cmd egoscale.AsyncCommand, msg string) (interface{}, error) {
 	return response, errorReq
 }
 
+// defaultParallelism represents the default maximum number of zones processed concurrently by forEachZone.
+const defaultParallelism = 4
+
 // forEachZone executes the function f for each specified zone, and return a multierror.Error containing all
-// errors that may have occurred during execution.
+// errors that may have occurred during execution. At most gParallelism zones are processed concurrently.
 func forEachZone(zones []string, f func(zone string) error) error {
+	return forEachParallel(len(zones), func(i int) error { return f(zones[i]) })
+}
+
+// forEachParallel executes the function f for each index in [0, n), and return a multierror.Error containing all
+// errors that may have occurred during execution. At most gParallelism calls are executed concurrently.
+func forEachParallel(n int, f func(i int) error) error {
 	meg := new(multierror.Group)
 
-	for _, zone := range zones {
-		zone := zone
+	parallelism := gParallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	sem := make(chan struct{}, parallelism)
+
+	for i := 0; i < n; i++ {
+		i := i
 		meg.Go(func() error {
-			return f(zone)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			return f(i)
 		})
 	}
 
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+)
+
+// resourceCacheEntry represents a resourceCache entry: the resource is
+// retrieved only once, even if requested concurrently.
+type resourceCacheEntry struct {
+	once sync.Once
+	v    interface{}
+	err  error
+}
+
+// resourceCache is a concurrency-safe cache of API resources, to be shared
+// across goroutines (e.g. the ones spawned by forEachZone) in order to avoid
+// retrieving the same resources repeatedly.
+type resourceCache struct {
+	mu      sync.Mutex
+	entries map[string]*resourceCacheEntry
+}
+
+func newResourceCache() *resourceCache {
+	return &resourceCache{entries: make(map[string]*resourceCacheEntry)}
+}
+
+// get returns the resource cached under key, calling fetch to retrieve it if
+// it isn't cached yet.
+func (c *resourceCache) get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = new(resourceCacheEntry)
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() { entry.v, entry.err = fetch() })
+
+	return entry.v, entry.err
+}
+
+// instanceType returns the Compute instance type identified by id.
+func (c *resourceCache) instanceType(
+	ctx context.Context,
+	client *exov2.Client,
+	zone, id string,
+) (*exov2.InstanceType, error) {
+	v, err := c.get("instance-type/"+id, func() (interface{}, error) {
+		return client.GetInstanceType(ctx, zone, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*exov2.InstanceType), nil
+}
+
+// template returns the Compute instance template identified by id in the
+// specified zone.
+func (c *resourceCache) template(
+	ctx context.Context,
+	client *exov2.Client,
+	zone, id string,
+) (*exov2.Template, error) {
+	v, err := c.get("template/"+zone+"/"+id, func() (interface{}, error) {
+		return client.GetTemplate(ctx, zone, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*exov2.Template), nil
+}
+
+// privateNetwork returns the Private Network identified by x (name or ID) in
+// the specified zone.
+func (c *resourceCache) privateNetwork(
+	ctx context.Context,
+	client *exov2.Client,
+	zone, x string,
+) (*exov2.PrivateNetwork, error) {
+	v, err := c.get("private-network/"+zone+"/"+x, func() (interface{}, error) {
+		return client.FindPrivateNetwork(ctx, zone, x)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*exov2.PrivateNetwork), nil
+}
@@ -9,7 +9,6 @@ import (
 	"os/user"
 	"path"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/exoscale/egoscale"
@@ -73,6 +72,11 @@ var (
 	gOutputTemplate string
 
 	gQuiet bool
+
+	gAccounts    []string
+	gAllAccounts bool
+
+	gParallelism int
 )
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -113,6 +117,9 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&gOutputFormat, "output-format", "O", "", "Output format (table|json|text), see \"exo output --help\" for more information")
 	RootCmd.PersistentFlags().StringVar(&gOutputTemplate, "output-template", "", "Template to use if output format is \"text\"")
 	RootCmd.PersistentFlags().BoolVarP(&gQuiet, "quiet", "Q", false, "Quiet mode (disable non-essential command output)")
+	RootCmd.PersistentFlags().StringSliceVar(&gAccounts, "accounts", nil, "Accounts to run the command against in parallel, supported by list/show commands only")
+	RootCmd.PersistentFlags().BoolVar(&gAllAccounts, "all-accounts", false, "Run the command against all configured accounts in parallel, supported by list/show commands only")
+	RootCmd.PersistentFlags().IntVar(&gParallelism, "parallelism", defaultParallelism, "Maximum number of concurrent API requests performed by multi-zone and bulk commands")
 	RootCmd.AddCommand(versionCmd)
 
 	// Don't attempt to load client configuration in testing mode.
@@ -298,20 +305,8 @@ func initConfig() {
 		log.Fatalf("error: could't find any configured account named %q", gAccountName)
 	}
 
-	if gCurrentAccount.Endpoint == "" {
-		if gCurrentAccount.ComputeEndpoint != "" {
-			gCurrentAccount.Endpoint = gCurrentAccount.ComputeEndpoint
-		} else {
-			gCurrentAccount.Endpoint = defaultEndpoint
-		}
-	}
-
-	if gCurrentAccount.Environment == "" {
-		gCurrentAccount.Environment = defaultEnvironment
-	}
-
-	if gCurrentAccount.DefaultZone == "" {
-		gCurrentAccount.DefaultZone = defaultZone
+	for i := range config.Accounts {
+		config.Accounts[i].setDefaults()
 	}
 
 	// if an output format isn't specified via cli argument, use
@@ -323,37 +318,6 @@ func initConfig() {
 			gOutputFormat = defaultOutputFormat
 		}
 	}
-
-	if gCurrentAccount.DNSEndpoint == "" {
-		gCurrentAccount.DNSEndpoint = buildDNSAPIEndpoint(gCurrentAccount.Endpoint)
-	}
-
-	if gCurrentAccount.DefaultTemplate == "" {
-		gCurrentAccount.DefaultTemplate = defaultTemplate
-	}
-
-	if gCurrentAccount.SosEndpoint == "" {
-		gCurrentAccount.SosEndpoint = defaultSosEndpoint
-	}
-
-	if gCurrentAccount.RunstatusEndpoint == "" {
-		gCurrentAccount.RunstatusEndpoint = defaultRunstatusEndpoint
-	}
-
-	if gCurrentAccount.ClientTimeout == 0 {
-		gCurrentAccount.ClientTimeout = defaultClientTimeout
-	}
-	clientTimeoutFromEnv := readFromEnv("EXOSCALE_API_TIMEOUT")
-	if clientTimeoutFromEnv != "" {
-		if t, err := strconv.Atoi(clientTimeoutFromEnv); err == nil {
-			gCurrentAccount.ClientTimeout = t
-		}
-	}
-
-	gCurrentAccount.Endpoint = strings.TrimRight(gCurrentAccount.Endpoint, "/")
-	gCurrentAccount.DNSEndpoint = strings.TrimRight(gCurrentAccount.DNSEndpoint, "/")
-	gCurrentAccount.SosEndpoint = strings.TrimRight(gCurrentAccount.SosEndpoint, "/")
-	gCurrentAccount.RunstatusEndpoint = strings.TrimRight(gCurrentAccount.RunstatusEndpoint, "/")
 }
 
 func isNonCredentialCmd(cmds ...string) bool {
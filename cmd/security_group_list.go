@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	egoscale "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
@@ -45,9 +46,13 @@ func (c *securityGroupListCmd) cmdPreRun(cmd *cobra.Command, args []string) erro
 }
 
 func (c *securityGroupListCmd) cmdRun(_ *cobra.Command, _ []string) error {
+	return c.outputFunc(c.cmdRunForAccount(cs.Client, gCurrentAccount))
+}
+
+func (c *securityGroupListCmd) cmdRunForAccount(client *egoscale.Client, acc *account) (outputter, error) {
 	ctx := exoapi.WithEndpoint(
 		gContext,
-		exoapi.NewReqEndpoint(gCurrentAccount.Environment, gCurrentAccount.DefaultZone),
+		exoapi.NewReqEndpoint(acc.Environment, acc.DefaultZone),
 	)
 
 	params := &oapi.ListSecurityGroupsParams{}
@@ -56,9 +61,9 @@ func (c *securityGroupListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 			Visibility: (*oapi.ListSecurityGroupsParamsVisibility)(&c.Visibility),
 		}
 	}
-	securityGroups, err := cs.FindSecurityGroups(ctx, gCurrentAccount.DefaultZone, params)
+	securityGroups, err := client.FindSecurityGroups(ctx, acc.DefaultZone, params)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	out := make(securityGroupListOutput, 0)
@@ -74,7 +79,7 @@ func (c *securityGroupListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		out = append(out, sg)
 	}
 
-	return c.outputFunc(&out, nil)
+	return &out, nil
 }
 
 func init() {
@@ -53,7 +53,7 @@ func (c *sksNodepoolListCmd) cmdRun(_ *cobra.Command, _ []string) error {
 	if c.Zone != "" {
 		zones = []string{c.Zone}
 	} else {
-		zones = allZones
+		zones = listZoneNames(cs.Client, gCurrentAccount)
 	}
 
 	out := make(sksNodepoolListOutput, 0)
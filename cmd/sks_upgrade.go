@@ -8,6 +8,7 @@ import (
 	"github.com/exoscale/cli/utils"
 	v2 "github.com/exoscale/egoscale/v2"
 	exoapi "github.com/exoscale/egoscale/v2/api"
+	"github.com/exoscale/egoscale/v2/oapi"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +20,7 @@ type sksUpgradeCmd struct {
 	Cluster string `cli-arg:"#" cli-usage:"NAME|ID"`
 	Version string `cli-arg:"#"`
 
+	Async bool   `cli-usage:"don't wait for the operation to complete, print its ID instead"`
 	Force bool   `cli-short:"f" cli-usage:"don't prompt for confirmation"`
 	Zone  string `cli-short:"z" cli-usage:"SKS cluster zone"`
 }
@@ -81,6 +83,24 @@ func (c *sksUpgradeCmd) cmdRun(_ *cobra.Command, _ []string) error {
 		}
 	}
 
+	if c.Async {
+		resp, err := cs.UpgradeSksClusterWithResponse(
+			ctx,
+			*cluster.ID,
+			oapi.UpgradeSksClusterJSONRequestBody{Version: c.Version},
+		)
+		if err != nil {
+			return err
+		}
+
+		op, err := asyncOperationFromResponse(resp, resp.JSON200)
+		if err != nil {
+			return err
+		}
+
+		return c.outputFunc(newOperationShowOutput(op, c.Zone), nil)
+	}
+
 	decorateAsyncOperation(fmt.Sprintf("Upgrading SKS cluster %q...", c.Cluster), func() {
 		err = cs.UpgradeSKSCluster(ctx, c.Zone, cluster, c.Version)
 	})
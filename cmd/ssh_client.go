@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const sshDialTimeout = 15 * time.Second
+
+// sshKnownHostsMu serializes accesses to the exo CLI known_hosts file.
+var sshKnownHostsMu sync.Mutex
+
+var (
+	sshAgentOnce   sync.Once
+	sshAgentClient agent.ExtendedAgent
+)
+
+// sshKnownHostsFilePath returns the path of the known_hosts file used by the
+// exo CLI native SSH client.
+func sshKnownHostsFilePath() string {
+	return path.Join(gConfigFolder, "known_hosts")
+}
+
+// sshHostKeyCallback returns an ssh.HostKeyCallback verifying remote hosts
+// keys against the exo CLI known_hosts file. Unknown hosts keys are trusted
+// on first use and recorded in the file, whereas keys not matching the
+// recorded ones are rejected.
+func sshHostKeyCallback() (ssh.HostKeyCallback, error) {
+	file := sshKnownHostsFilePath()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+
+	check, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load known hosts file: %w", err)
+	}
+
+	trusted := make(map[string]string)
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		sshKnownHostsMu.Lock()
+		defer sshKnownHostsMu.Unlock()
+
+		host := knownhosts.Normalize(hostname)
+		if k, ok := trusted[host]; ok {
+			if k != string(key.Marshal()) {
+				return fmt.Errorf("host key mismatch for %s", hostname)
+			}
+			return nil
+		}
+
+		err := check(hostname, remote, key)
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			if err != nil {
+				return fmt.Errorf("%w (known hosts file: %s)", err, file)
+			}
+			return nil
+		}
+
+		// Unknown host: trust on first use.
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if _, err := fmt.Fprintln(f, knownhosts.Line([]string{host}, key)); err != nil {
+			return err
+		}
+		trusted[host] = string(key.Marshal())
+
+		return nil
+	}, nil
+}
+
+// sshSigners returns the SSH signers available from the SSH agent (if
+// running) and from the specified private key files, as well as the user's
+// default SSH private keys. Missing, unreadable or passphrase-protected keys
+// are skipped.
+func sshSigners(keyFiles ...string) []ssh.Signer {
+	signers := make([]ssh.Signer, 0)
+
+	if agentClient := sshAgent(); agentClient != nil {
+		if agentSigners, err := agentClient.Signers(); err == nil {
+			signers = append(signers, agentSigners...)
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, k := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			keyFiles = append(keyFiles, filepath.Join(home, ".ssh", k))
+		}
+	}
+
+	for _, keyFile := range keyFiles {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			continue
+		}
+
+		signers = append(signers, signer)
+	}
+
+	return signers
+}
+
+// sshAgent returns a client to the SSH agent, or nil if no agent is running.
+// As agent signers use the agent connection for every authentication, a
+// single connection is opened for the whole command execution and shared by
+// all SSH clients.
+func sshAgent() agent.ExtendedAgent {
+	sshAgentOnce.Do(func() {
+		if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+			if conn, err := net.Dial("unix", sock); err == nil {
+				sshAgentClient = agent.NewClient(conn)
+			}
+		}
+	})
+
+	return sshAgentClient
+}
+
+// newSSHClientConfig returns an SSH client configuration authenticating as
+// user with the signers returned by sshSigners.
+func newSSHClientConfig(user string, keyFiles ...string) (*ssh.ClientConfig, error) {
+	hostKeyCallback, err := sshHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	signers := sshSigners(keyFiles...)
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no SSH key available (tried SSH agent and default key files)")
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	}, nil
+}
+
+// instanceSSHTarget represents the information required to connect to a
+// Compute instance via SSH.
+type instanceSSHTarget struct {
+	host    string
+	user    string
+	keyFile string
+}
+
+// newInstanceSSHTarget returns the SSH connection information of a Compute
+// instance: its IP address (the public IPv4 address by default, the IPv6
+// address if requested, or the address leased on the specified managed
+// Private Network), the login user (if not specified, the default user of the
+// instance template) and the single-use SSH key generated at instance
+// creation if any. Templates and Private Networks are retrieved through the
+// cache specified.
+func newInstanceSSHTarget(
+	ctx context.Context,
+	instance *exov2.Instance,
+	login string,
+	ipv6 bool,
+	privateNetwork string,
+	cache *resourceCache,
+) (*instanceSSHTarget, error) {
+	target := instanceSSHTarget{
+		user:    login,
+		keyFile: getInstanceSSHKeyPath(*instance.ID),
+	}
+
+	ctx = exoapi.WithEndpoint(ctx, exoapi.NewReqEndpoint(gCurrentAccount.Environment, *instance.Zone))
+
+	switch {
+	case privateNetwork != "":
+		pn, err := cache.privateNetwork(ctx, cs.Client, *instance.Zone, privateNetwork)
+		if err != nil {
+			if errors.Is(err, exoapi.ErrNotFound) {
+				return nil, fmt.Errorf("private network %q not found in zone %q", privateNetwork, *instance.Zone)
+			}
+			return nil, fmt.Errorf("error retrieving Private Network: %w", err)
+		}
+
+		for _, lease := range pn.Leases {
+			if *lease.InstanceID == *instance.ID {
+				target.host = lease.IPAddress.String()
+				break
+			}
+		}
+		if target.host == "" {
+			return nil, fmt.Errorf(
+				"instance %q has no address leased on Private Network %q (only managed Private Networks are supported)",
+				*instance.Name,
+				privateNetwork,
+			)
+		}
+
+	case ipv6:
+		if instance.IPv6Address == nil {
+			return nil, fmt.Errorf("instance %q has no IPv6 address", *instance.Name)
+		}
+		target.host = instance.IPv6Address.String()
+
+	default:
+		if instance.PublicIPAddress == nil {
+			return nil, fmt.Errorf("instance %q has no public IP address", *instance.Name)
+		}
+		target.host = instance.PublicIPAddress.String()
+	}
+
+	if target.user == "" {
+		template, err := cache.template(ctx, cs.Client, *instance.Zone, *instance.TemplateID)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving instance template: %w", err)
+		}
+		if template.DefaultUser != nil {
+			target.user = *template.DefaultUser
+		}
+	}
+
+	return &target, nil
+}
+
+// destination returns the target in the [USER@]HOST form.
+func (t *instanceSSHTarget) destination() string {
+	if t.user == "" {
+		return t.host
+	}
+	return t.user + "@" + t.host
+}
+
+// dial opens an SSH connection to the target, logging in as "root" if no
+// user has been resolved.
+func (t *instanceSSHTarget) dial() (*ssh.Client, error) {
+	user := t.user
+	if user == "" {
+		user = "root"
+	}
+
+	config, err := newSSHClientConfig(user, t.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.Dial("tcp", net.JoinHostPort(t.host, "22"), config)
+}
+
+// findBastionInstance returns the Compute instance matching the specified
+// name or ID in the zones specified, to be used as SSH bastion host.
+func findBastionInstance(zones []string, v string) (*exov2.Instance, error) {
+	instances, err := findInstances(zones, []string{v}, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find bastion instance: %w", err)
+	}
+
+	if len(instances) > 1 {
+		return nil, fmt.Errorf("multiple instances named %q found, please specify the bastion instance ID", v)
+	}
+
+	return instances[0], nil
+}
@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"io"
+	"net/http"
 	"os"
 	"path"
 	"strings"
@@ -43,7 +43,15 @@ func init() {
 		// We have to wait until the actual command execution to assign a value to this variable
 		// because some of the global variables used are not initialized before Cobra executes
 		// the command.
+		transport, err := newHTTPTransport(gCurrentAccount)
+		if err != nil {
+			return fmt.Errorf("unable to initialize HTTP transport: %w", err)
+		}
+
 		storageCommonConfigOptFns = []func(*awsconfig.LoadOptions) error{
+			// Custom HTTP client network settings (proxy, TLS)
+			awsconfig.WithHTTPClient(&http.Client{Transport: transport}),
+
 			// Custom HTTP client User-Agent
 			awsconfig.WithAPIOptions([]func(*middleware.Stack) error{
 				awsmiddleware.AddUserAgentKeyValue("Exoscale-CLI",
@@ -217,13 +225,9 @@ func storageClientOptZoneFromBucket(bucket string) storageClientOpt {
 }
 
 func newStorageClient(opts ...storageClientOpt) (*storageClient, error) {
-	var (
-		client = storageClient{
-			zone: gCurrentAccount.DefaultZone,
-		}
-
-		caCerts io.Reader
-	)
+	client := storageClient{
+		zone: gCurrentAccount.DefaultZone,
+	}
 
 	for _, opt := range opts {
 		if err := opt(&client); err != nil {
@@ -250,8 +254,6 @@ func newStorageClient(opts ...storageClientOpt) (*storageClient, error) {
 				gCurrentAccount.Key,
 				gCurrentAccount.APISecret(),
 				"")),
-
-			awsconfig.WithCustomCABundle(caCerts),
 		)...)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// tlsVersions maps the supported values of the "tlsMinVersion" account
+// setting to their crypto/tls counterpart.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newHTTPTransport returns an HTTP transport configured according to the
+// network settings of the specified account (HTTP proxy, custom CA bundle,
+// client certificate and minimum TLS version). If the account doesn't
+// specify any of these settings, the returned transport behaves like
+// http.DefaultTransport.
+func newHTTPTransport(acc *account) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if acc.Proxy != "" {
+		proxyURL, err := url.Parse(acc.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", acc.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if acc.CACertificateFile == "" &&
+		acc.ClientCertificateFile == "" &&
+		acc.TLSMinVersion == "" {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if acc.TLSMinVersion != "" {
+		v, ok := tlsVersions[acc.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS minimum version %q", acc.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = v
+	}
+
+	if acc.CACertificateFile != "" {
+		pem, err := os.ReadFile(acc.CACertificateFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate file: %w", err)
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificate found in %q", acc.CACertificateFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if acc.ClientCertificateFile != "" {
+		keyFile := acc.ClientKeyFile
+		if keyFile == "" {
+			keyFile = acc.ClientCertificateFile
+		}
+
+		cert, err := tls.LoadX509KeyPair(acc.ClientCertificateFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -49,6 +49,12 @@ input parameters can be supplied either via stdin or using Shorthands[2].
 			}
 		}
 
+		transport, err := newHTTPTransport(gCurrentAccount)
+		if err != nil {
+			return fmt.Errorf("unable to initialize HTTP transport: %w", err)
+		}
+		x.SetClientTransport(transport)
+
 		x.SetClientUserAgent(fmt.Sprintf(
 			"Exoscale-CLI-X/%s (%s) %s",
 			gVersion,
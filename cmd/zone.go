@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/exoscale/egoscale"
+	exov2 "github.com/exoscale/egoscale/v2"
+	exoapi "github.com/exoscale/egoscale/v2/api"
 	"github.com/spf13/cobra"
 )
 
@@ -13,8 +19,11 @@ const (
 	zoneHelp = "zone NAME|ID (ch-dk-2|ch-gva-2|at-vie-1|de-fra-1|bg-sof-1|de-muc-1)"
 )
 
-// allZones represents the list of known Exoscale zones, in case we need it without performing API lookup.
-var allZones = []string{
+// zonesCacheTTL represents the duration for which the list of zones discovered via the API is cached on disk.
+const zonesCacheTTL = 24 * time.Hour
+
+// defaultZones represents the list of known Exoscale zones, used as fallback in case zones discovery fails.
+var defaultZones = []string{
 	"at-vie-1",
 	"bg-sof-1",
 	"ch-dk-2",
@@ -23,6 +32,56 @@ var allZones = []string{
 	"de-muc-1",
 }
 
+// zonesCache represents the on-disk cache of zones discovered via the API.
+type zonesCache struct {
+	Zones     []string  `json:"zones"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// zonesCacheFilePath returns the path of the zones cache file for the specified API environment.
+func zonesCacheFilePath(environment string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "exoscale", fmt.Sprintf("zones-%s.json", environment)), nil
+}
+
+// listZoneNames returns the names of the zones available in the API environment of the specified account. Zones are
+// discovered using the API and cached on disk for zonesCacheTTL; if discovery fails, the defaultZones list is
+// returned.
+func listZoneNames(client *exov2.Client, acc *account) []string {
+	cacheFile, err := zonesCacheFilePath(acc.Environment)
+	if err == nil {
+		if data, err := os.ReadFile(cacheFile); err == nil {
+			var cache zonesCache
+			if err := json.Unmarshal(data, &cache); err == nil &&
+				len(cache.Zones) > 0 &&
+				time.Since(cache.UpdatedAt) < zonesCacheTTL {
+				return cache.Zones
+			}
+		}
+	}
+
+	ctx := exoapi.WithEndpoint(gContext, exoapi.NewReqEndpoint(acc.Environment, acc.DefaultZone))
+	zones, err := client.ListZones(ctx)
+	if err != nil || len(zones) == 0 {
+		return defaultZones
+	}
+	sort.Strings(zones)
+
+	if cacheFile != "" {
+		if data, err := json.Marshal(zonesCache{Zones: zones, UpdatedAt: time.Now()}); err == nil {
+			if err := os.MkdirAll(filepath.Dir(cacheFile), 0o700); err == nil {
+				_ = os.WriteFile(cacheFile, data, 0o600)
+			}
+		}
+	}
+
+	return zones
+}
+
 type zoneListItemOutput struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -28,6 +28,8 @@ require (
 	github.com/nicksnyder/go-i18n v1.10.0 // indirect
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/pkg/errors v0.9.1
+	github.com/pkg/sftp v1.13.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/rs/zerolog v1.18.0
 	github.com/spf13/cobra v1.3.0
 	github.com/spf13/pflag v1.0.5